@@ -0,0 +1,110 @@
+package client
+
+import (
+	"sort"
+	"yontrack/config"
+)
+
+// Account is the account authenticated by the credentials of a configuration
+type Account struct {
+	Name     string `json:"name"`
+	FullName string `json:"fullName"`
+	Email    string `json:"email"`
+	// Global roles granted to the account, directly or through its groups
+	GlobalRoles []string `json:"globalRoles"`
+}
+
+// Ping checks that the server can be reached, whatever its HTTP response
+func (c *Client) Ping() error {
+	_, err := c.http.R().Get("/")
+	return err
+}
+
+// GetVersion returns the version of Ontrack
+func GetVersion(cfg *config.Config) (string, error) {
+	var data struct {
+		Info struct {
+			Version struct {
+				Display string
+			}
+		}
+	}
+	if err := GraphQLCall(cfg, `
+		{
+			info {
+				version {
+					display
+				}
+			}
+		}
+	`, map[string]interface{}{}, &data); err != nil {
+		return "", err
+	}
+	return data.Info.Version.Display, nil
+}
+
+// GetAccount returns the account authenticated by the credentials of the configuration.
+// An AuthenticationError is returned when the credentials are not recognized.
+func GetAccount(cfg *config.Config) (*Account, error) {
+	type globalRole struct {
+		ID string
+	}
+	var data struct {
+		User struct {
+			Account *struct {
+				Name       string
+				FullName   string
+				Email      string
+				GlobalRole *globalRole
+				Groups     []struct {
+					GlobalRole *globalRole
+				}
+			}
+		}
+	}
+	if err := GraphQLCall(cfg, `
+		{
+			user {
+				account {
+					name
+					fullName
+					email
+					globalRole {
+						id
+					}
+					groups {
+						globalRole {
+							id
+						}
+					}
+				}
+			}
+		}
+	`, map[string]interface{}{}, &data); err != nil {
+		return nil, err
+	}
+	account := data.User.Account
+	if account == nil {
+		return nil, &AuthenticationError{Message: "the credentials are not recognized by Ontrack (anonymous access)"}
+	}
+	roles := make(map[string]bool)
+	if account.GlobalRole != nil {
+		roles[account.GlobalRole.ID] = true
+	}
+	for _, group := range account.Groups {
+		if group.GlobalRole != nil {
+			roles[group.GlobalRole.ID] = true
+		}
+	}
+	result := &Account{
+		Name:        account.Name,
+		FullName:    account.FullName,
+		Email:       account.Email,
+		GlobalRoles: make([]string, 0, len(roles)),
+	}
+	for role := range roles {
+		result.GlobalRoles = append(result.GlobalRoles, role)
+	}
+	sort.Strings(result.GlobalRoles)
+	return result, nil
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"yontrack/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAccount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"user":{"account":{
+			"name":"ci","fullName":"CI robot","email":"ci@example.com",
+			"globalRole":{"id":"CONTROLLER"},
+			"groups":[{"globalRole":{"id":"READ_ONLY"}},{"globalRole":null},{"globalRole":{"id":"CONTROLLER"}}]
+		}}}}`))
+	}))
+	defer server.Close()
+
+	account, err := GetAccount(&config.Config{URL: server.URL, Token: "abc"})
+	require.NoError(t, err)
+	assert.Equal(t, &Account{
+		Name:        "ci",
+		FullName:    "CI robot",
+		Email:       "ci@example.com",
+		GlobalRoles: []string{"CONTROLLER", "READ_ONLY"},
+	}, account)
+}
+
+func TestGetAccount_Anonymous(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"user":{"account":null}}}`))
+	}))
+	defer server.Close()
+
+	_, err := GetAccount(&config.Config{URL: server.URL})
+	require.Error(t, err)
+	assert.True(t, IsAuthorization(err))
+}
+
+func TestClient_Ping(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	url := server.URL
+
+	// Any HTTP response means that the server can be reached
+	assert.NoError(t, New(&config.Config{URL: url}).Ping())
+
+	server.Close()
+	assert.Error(t, New(&config.Config{URL: url}).Ping())
+}
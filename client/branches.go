@@ -0,0 +1,250 @@
+package client
+
+import (
+	"regexp"
+	"strconv"
+	"yontrack/config"
+)
+
+// Branch is the representation of an Ontrack branch
+type Branch struct {
+	ID              string           `json:"id"`
+	Name            string           `json:"name"`
+	Description     string           `json:"description"`
+	Disabled        bool             `json:"disabled"`
+	Creation        Signature        `json:"creation"`
+	Project         BranchProject    `json:"project"`
+	PromotionLevels []PromotionLevel `json:"promotionLevels,omitempty"`
+	Builds          []BranchBuild    `json:"builds"`
+}
+
+// BranchProject is the project a branch belongs to
+type BranchProject struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// PromotionLevel is a promotion level defined for a branch
+type PromotionLevel struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// BranchBuild is a build in a branch, with its release and its last promotions
+type BranchBuild struct {
+	ID              string    `json:"id"`
+	Name            string    `json:"name"`
+	Creation        Signature `json:"creation"`
+	ReleaseProperty *struct {
+		Value struct {
+			Name string `json:"name"`
+		} `json:"value"`
+	} `json:"releaseProperty"`
+	PromotionRuns []struct {
+		PromotionLevel struct {
+			Name string `json:"name"`
+		} `json:"promotionLevel"`
+	} `json:"promotionRuns"`
+}
+
+// BranchFilter restricts the list of branches returned by GetBranches
+type BranchFilter struct {
+	// Regular expression on the branch name
+	Name string
+	// If not nil, gets only the enabled (true) or disabled (false) branches
+	Enabled *bool
+	// Maximum number of branches to return (no limit if 0)
+	Count int
+}
+
+// IntID returns the ID of the branch as an integer, as expected by the mutations
+func (b *Branch) IntID() (int, error) {
+	return strconv.Atoi(b.ID)
+}
+
+// LastBuild returns the most recent build loaded for this branch, or nil if there is none
+func (b *Branch) LastBuild() *BranchBuild {
+	if len(b.Builds) == 0 {
+		return nil
+	}
+	return &b.Builds[0]
+}
+
+// IntID returns the ID of the build as an integer, as expected by the mutations
+func (b *BranchBuild) IntID() (int, error) {
+	return strconv.Atoi(b.ID)
+}
+
+// Release returns the release label of the build, if any
+func (b *BranchBuild) Release() string {
+	if b.ReleaseProperty == nil {
+		return ""
+	}
+	return b.ReleaseProperty.Value.Name
+}
+
+// Promotions returns the names of the promotion levels of the build
+func (b *BranchBuild) Promotions() []string {
+	promotions := make([]string, 0, len(b.PromotionRuns))
+	for _, run := range b.PromotionRuns {
+		promotions = append(promotions, run.PromotionLevel.Name)
+	}
+	return promotions
+}
+
+const branchFields = `
+	id
+	name
+	description
+	disabled
+	creation {
+		time
+		user
+	}
+	project {
+		id
+		name
+	}
+`
+
+const branchBuildFields = `
+	id
+	name
+	creation {
+		time
+		user
+	}
+	releaseProperty {
+		value
+	}
+	promotionRuns(lastPerLevel: true) {
+		promotionLevel {
+			name
+		}
+	}
+`
+
+// GetBranches loads the branches of a project, ordered from the most recent activity,
+// together with their last build
+func GetBranches(cfg *config.Config, project string, filter BranchFilter) ([]Branch, error) {
+	var data struct {
+		Projects []struct {
+			Branches []Branch
+		}
+	}
+	var count interface{}
+	if filter.Count > 0 {
+		count = filter.Count
+	}
+	var name interface{}
+	if filter.Name != "" {
+		name = filter.Name
+	}
+	if err := GraphQLCall(cfg, `
+		query GetBranches($project: String!, $name: String, $enabled: Boolean, $count: Int) {
+			projects(name: $project) {
+				branches(name: $name, enabled: $enabled, count: $count, order: true) {
+					`+branchFields+`
+					builds(count: 1) {
+						`+branchBuildFields+`
+					}
+				}
+			}
+		}
+	`, map[string]interface{}{
+		"project": project,
+		"name":    name,
+		"enabled": filter.Enabled,
+		"count":   count,
+	}, &data); err != nil {
+		return nil, err
+	}
+	if len(data.Projects) == 0 {
+		return nil, NewNotFoundError("project %s not found", project)
+	}
+	return data.Projects[0].Branches, nil
+}
+
+// GetBranchByName loads a branch using its project and its name, together with its promotion
+// levels and its last builds. A NotFoundError is returned if the branch does not exist.
+func GetBranchByName(cfg *config.Config, project string, branch string, builds int) (*Branch, error) {
+	var data struct {
+		Branches []Branch
+	}
+	if err := GraphQLCall(cfg, `
+		query GetBranchByName($project: String!, $branch: String!, $builds: Int!) {
+			branches(project: $project, name: $branch) {
+				`+branchFields+`
+				promotionLevels {
+					name
+					description
+				}
+				builds(count: $builds) {
+					`+branchBuildFields+`
+				}
+			}
+		}
+	`, map[string]interface{}{
+		"project": project,
+		"branch":  "^" + regexp.QuoteMeta(branch) + "$",
+		"builds":  builds,
+	}, &data); err != nil {
+		return nil, err
+	}
+	if len(data.Branches) == 0 {
+		return nil, NewNotFoundError("branch %s not found in project %s", branch, project)
+	}
+	return &data.Branches[0], nil
+}
+
+// SetBranchDisabled disables or enables a branch
+func SetBranchDisabled(cfg *config.Config, id int, disabled bool) error {
+	var data map[string]struct {
+		Errors []GraphQLError
+	}
+	mutation := "enableBranch"
+	if disabled {
+		mutation = "disableBranch"
+	}
+	if err := GraphQLCall(cfg, `
+		mutation SetBranchState($id: Int!) {
+			`+mutation+`(input: {id: $id}) {
+				errors {
+					message
+					exception
+					location
+				}
+			}
+		}
+	`, map[string]interface{}{
+		"id": id,
+	}, &data); err != nil {
+		return err
+	}
+	return CheckDataErrors(data[mutation].Errors)
+}
+
+// DeleteBranch deletes a branch and all its builds
+func DeleteBranch(cfg *config.Config, id int) error {
+	var data struct {
+		DeleteBranchById struct {
+			Errors []GraphQLError
+		}
+	}
+	if err := GraphQLCall(cfg, `
+		mutation DeleteBranch($id: Int!) {
+			deleteBranchById(input: {id: $id}) {
+				errors {
+					message
+					exception
+					location
+				}
+			}
+		}
+	`, map[string]interface{}{
+		"id": id,
+	}, &data); err != nil {
+		return err
+	}
+	return CheckDataErrors(data.DeleteBranchById.Errors)
+}
@@ -0,0 +1,28 @@
+package client
+
+import "yontrack/config"
+
+// DeleteBuild deletes a build
+func DeleteBuild(cfg *config.Config, id int) error {
+	var data struct {
+		DeleteBuildById struct {
+			Errors []GraphQLError
+		}
+	}
+	if err := GraphQLCall(cfg, `
+		mutation DeleteBuild($id: Int!) {
+			deleteBuildById(input: {id: $id}) {
+				errors {
+					message
+					exception
+					location
+				}
+			}
+		}
+	`, map[string]interface{}{
+		"id": id,
+	}, &data); err != nil {
+		return err
+	}
+	return CheckDataErrors(data.DeleteBuildById.Errors)
+}
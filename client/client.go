@@ -0,0 +1,195 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	config "yontrack/config"
+	"yontrack/utils"
+
+	resty "github.com/go-resty/resty/v2"
+)
+
+// Client is a long-lived connection to a remote Ontrack server.
+// The underlying HTTP connections are kept alive and reused between calls.
+type Client struct {
+	cfg  *config.Config
+	http *resty.Client
+}
+
+// Option customizes a Client when it's created
+type Option func(*Client)
+
+// WithTimeout sets the timeout of each HTTP request
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.http.SetTimeout(timeout)
+	}
+}
+
+// WithHeader adds a header to all the requests
+func WithHeader(name string, value string) Option {
+	return func(c *Client) {
+		c.http.SetHeader(name, value)
+	}
+}
+
+// WithUserAgent overrides the default user agent
+func WithUserAgent(userAgent string) Option {
+	return WithHeader("User-Agent", userAgent)
+}
+
+// WithTransport replaces the HTTP transport, typically for tests or for proxies
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		c.http.SetTransport(transport)
+	}
+}
+
+// New creates a client for the given configuration
+func New(cfg *config.Config, options ...Option) *Client {
+	rest := resty.New()
+	rest.SetHostURL(cfg.URL)
+	rest.SetDebug(config.GraphQLLogging)
+	rest.SetHeader("User-Agent", UserAgent())
+	rest.OnRequestLog(maskRequestLog)
+	rest.OnResponseLog(maskResponseLog)
+
+	// The credentials must never appear in the logs
+	utils.Secrets.AddValue(cfg.Token)
+	utils.Secrets.AddValue(cfg.Password)
+
+	if cfg.Token != "" {
+		rest.SetHeader("X-Ontrack-Token", cfg.Token)
+	} else if cfg.Username != "" {
+		rest.SetBasicAuth(cfg.Username, cfg.Password)
+	}
+
+	if cfg.ConnectionRetry.MaxCount != 0 {
+		rest.SetRetryCount(cfg.ConnectionRetry.MaxCount).
+			SetRetryMaxWaitTime(time.Duration(cfg.ConnectionRetry.MaxWaitTimeSec) * time.Second).
+			AddRetryCondition(func(r *resty.Response, err error) bool {
+				return err != nil || r.StatusCode() == http.StatusRequestTimeout || r.StatusCode() >= 500
+			})
+	}
+
+	if cfg.TimeoutSec > 0 {
+		rest.SetTimeout(time.Duration(cfg.TimeoutSec) * time.Second)
+	}
+
+	c := &Client{
+		cfg:  cfg,
+		http: rest,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// maskRequestLog hides the secrets in the requests logged by --graphql-log
+func maskRequestLog(log *resty.RequestLog) error {
+	maskHeaders(log.Header)
+	log.Body = utils.Secrets.Mask(log.Body)
+	return nil
+}
+
+// maskResponseLog hides the secrets in the responses logged by --graphql-log
+func maskResponseLog(log *resty.ResponseLog) error {
+	maskHeaders(log.Header)
+	log.Body = utils.Secrets.Mask(log.Body)
+	return nil
+}
+
+func maskHeaders(header http.Header) {
+	for name, values := range header {
+		for index, value := range values {
+			if name == "Authorization" {
+				header[name][index] = utils.MaskedValue
+			} else {
+				header[name][index] = utils.Secrets.MaskValue(name, value)
+			}
+		}
+	}
+}
+
+// Config returns the configuration this client was created for
+func (c *Client) Config() *config.Config {
+	return c.cfg
+}
+
+// Get downloads a resource from Ontrack. The path is either relative to the Ontrack URL or absolute.
+// Absolute URLs outside of Ontrack are refused, since the credentials would be sent along.
+func (c *Client) Get(path string) ([]byte, error) {
+	if !c.IsOntrackURL(path) {
+		return nil, fmt.Errorf("%s is not an Ontrack URL, the Ontrack credentials are not sent to it", path)
+	}
+	return getResource(c.http, path)
+}
+
+// GetAnonymous downloads a resource which is not hosted by Ontrack, without sending any credentials
+func (c *Client) GetAnonymous(url string) ([]byte, error) {
+	rest := resty.New()
+	rest.SetHeader("User-Agent", UserAgent())
+	rest.SetTimeout(c.http.GetClient().Timeout)
+	rest.SetTransport(c.http.GetClient().Transport)
+	return getResource(rest, url)
+}
+
+// IsOntrackURL checks if the path is relative to the Ontrack URL, or if it's an absolute URL below it
+func (c *Client) IsOntrackURL(path string) bool {
+	target, err := url.Parse(path)
+	if err != nil {
+		return false
+	}
+	if !target.IsAbs() && target.Host == "" {
+		return true
+	}
+	base, err := url.Parse(c.http.HostURL)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(target.Scheme, base.Scheme) &&
+		strings.EqualFold(target.Host, base.Host) &&
+		(target.Path == base.Path || strings.HasPrefix(target.Path, strings.TrimSuffix(base.Path, "/")+"/"))
+}
+
+func getResource(rest *resty.Client, path string) ([]byte, error) {
+	resp, err := rest.R().Get(path)
+	if err != nil {
+		return nil, err
+	}
+	if resp.IsError() {
+		return nil, &HTTPError{
+			StatusCode: resp.StatusCode(),
+			Message:    fmt.Sprintf("%s: %s", path, resp.Status()),
+		}
+	}
+	return resp.Body(), nil
+}
+
+// UserAgent returns the default user agent sent by the CLI
+func UserAgent() string {
+	return fmt.Sprintf("yontrack/%s", config.Version)
+}
+
+// Shared clients, one per configuration
+var (
+	clientsLock sync.Mutex
+	clients     = make(map[config.Config]*Client)
+)
+
+// ForConfig returns the client shared by all the calls using the same configuration
+func ForConfig(cfg *config.Config) *Client {
+	clientsLock.Lock()
+	defer clientsLock.Unlock()
+	if c, ok := clients[*cfg]; ok {
+		return c
+	}
+	c := New(cfg)
+	clients[*cfg] = c
+	return c
+}
@@ -0,0 +1,184 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"yontrack/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_GraphQL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/graphql", r.URL.Path)
+		assert.Equal(t, "my-token", r.Header.Get("X-Ontrack-Token"))
+		assert.Equal(t, UserAgent(), r.Header.Get("User-Agent"))
+
+		var body struct {
+			Query     string
+			Variables map[string]interface{}
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "{ projects { name } }", body.Query)
+
+		_, _ = w.Write([]byte(`{"data":{"projects":[{"name":"yontrack"}]}}`))
+	}))
+	defer server.Close()
+
+	c := New(&config.Config{URL: server.URL + "/", Token: "my-token"})
+
+	var data struct {
+		Projects []struct {
+			Name string
+		}
+	}
+	require.NoError(t, c.GraphQL("{ projects { name } }", map[string]interface{}{}, &data))
+	require.Len(t, data.Projects, 1)
+	assert.Equal(t, "yontrack", data.Projects[0].Name)
+}
+
+func TestClient_Options(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "custom-agent", r.Header.Get("User-Agent"))
+		assert.Equal(t, "value", r.Header.Get("X-Custom"))
+		assert.Equal(t, "yes", r.Header.Get("X-Transport"))
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	}))
+	defer server.Close()
+
+	c := New(&config.Config{URL: server.URL},
+		WithUserAgent("custom-agent"),
+		WithHeader("X-Custom", "value"),
+		WithTransport(roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			r.Header.Set("X-Transport", "yes")
+			return http.DefaultTransport.RoundTrip(r)
+		})),
+	)
+
+	var data interface{}
+	require.NoError(t, c.GraphQL("{ info { version { display } } }", nil, &data))
+}
+
+func TestClient_ConnectionReuse(t *testing.T) {
+	var connections int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&connections, 1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	c := New(&config.Config{URL: server.URL})
+	for i := 0; i < 3; i++ {
+		var data interface{}
+		require.NoError(t, c.GraphQL("{ projects { name } }", nil, &data))
+	}
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&connections))
+}
+
+func TestClient_Disabled(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	c := New(&config.Config{URL: server.URL, Disabled: true})
+
+	var data interface{}
+	require.NoError(t, c.GraphQL("{ projects { name } }", nil, &data))
+	assert.False(t, called)
+}
+
+func TestForConfig_Shared(t *testing.T) {
+	cfg := config.Config{Name: "test", URL: "http://localhost:8080"}
+	copied := cfg
+
+	assert.Same(t, ForConfig(&cfg), ForConfig(&copied))
+	assert.NotSame(t, ForConfig(&cfg), ForConfig(&config.Config{Name: "other", URL: "http://localhost:8080"}))
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestClient_Get(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "my-token", r.Header.Get("X-Ontrack-Token"))
+		if r.URL.Path == "/schema.json" {
+			_, _ = w.Write([]byte(`{"type":"object"}`))
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := New(&config.Config{URL: server.URL, Token: "my-token"})
+
+	body, err := c.Get("/schema.json")
+	require.NoError(t, err)
+	assert.Equal(t, `{"type":"object"}`, string(body))
+
+	_, err = c.Get(server.URL + "/missing.json")
+	var httpError *HTTPError
+	require.True(t, errors.As(err, &httpError))
+	assert.Equal(t, http.StatusNotFound, httpError.StatusCode)
+}
+
+func TestClient_Get_OutsideOntrack(t *testing.T) {
+	var calls int32
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		assert.Empty(t, r.Header.Get("X-Ontrack-Token"))
+		assert.Empty(t, r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"type":"object"}`))
+	}))
+	defer other.Close()
+
+	c := New(&config.Config{URL: "http://ontrack.example.com/ontrack", Token: "my-token"})
+
+	// The credentials are never sent outside of Ontrack
+	_, err := c.Get(other.URL + "/schema.json")
+	assert.Error(t, err)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls))
+
+	body, err := c.GetAnonymous(other.URL + "/schema.json")
+	require.NoError(t, err)
+	assert.Equal(t, `{"type":"object"}`, string(body))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestClient_IsOntrackURL(t *testing.T) {
+	c := New(&config.Config{URL: "https://ontrack.example.com/ontrack/"})
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{"/schema.json", true},
+		{"schema.json", true},
+		{"https://ontrack.example.com/ontrack", true},
+		{"https://ONTRACK.example.com/ontrack/schema.json", true},
+		{"https://ontrack.example.com/ontrack-other/schema.json", false},
+		{"https://ontrack.example.com/schema.json", false},
+		{"http://ontrack.example.com/ontrack/schema.json", false},
+		{"https://ontrack.example.com:8443/ontrack/schema.json", false},
+		{"https://example.com/ontrack/schema.json", false},
+		{"//example.com/ontrack/schema.json", false},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, c.IsOntrackURL(test.path), test.path)
+	}
+}
@@ -0,0 +1,199 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrorCategory classifies the errors returned by Ontrack so that callers
+// can react to them without parsing messages
+type ErrorCategory string
+
+const (
+	// CategoryUnknown is used when the error cannot be classified
+	CategoryUnknown ErrorCategory = "unknown"
+	// CategoryNotFound is used when an entity (project, branch, build...) does not exist
+	CategoryNotFound ErrorCategory = "not-found"
+	// CategoryAlreadyExists is used when an entity with the same name already exists
+	CategoryAlreadyExists ErrorCategory = "already-exists"
+	// CategoryAuthorization is used when the user is not authenticated or not allowed
+	CategoryAuthorization ErrorCategory = "authorization"
+	// CategoryValidation is used when the input is rejected by Ontrack
+	CategoryValidation ErrorCategory = "validation"
+)
+
+// GraphQLError is an error returned by Ontrack, either at the top level of a
+// GraphQL response or in the payload of a mutation
+type GraphQLError struct {
+	// The error message
+	Message string `json:"message"`
+	// Usually the FQCN of the exception on the server side (mutation payloads only)
+	Exception string `json:"exception,omitempty"`
+	// Additional information about the location of this error (mutation payloads only)
+	Location string `json:"location,omitempty"`
+	// Path to the field in error (top level errors only)
+	Path []interface{} `json:"path,omitempty"`
+	// Additional information (top level errors only)
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+func (e *GraphQLError) Error() string {
+	return e.Message
+}
+
+// ExceptionName returns the exception associated with this error, either directly
+// or through the extensions
+func (e *GraphQLError) ExceptionName() string {
+	if e.Exception != "" {
+		return e.Exception
+	}
+	for _, key := range []string{"exception", "classification", "code"} {
+		if value, ok := e.Extensions[key].(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// Category classifies this error based on its exception
+func (e *GraphQLError) Category() ErrorCategory {
+	exception := strings.ToLower(e.ExceptionName())
+	switch {
+	case exception == "":
+		return CategoryUnknown
+	case strings.Contains(exception, "notfound") || strings.Contains(exception, "not_found"):
+		return CategoryNotFound
+	case strings.Contains(exception, "alreadydefined") ||
+		strings.Contains(exception, "alreadyexists") ||
+		strings.Contains(exception, "already_exists") ||
+		strings.Contains(exception, "duplicate"):
+		return CategoryAlreadyExists
+	case strings.Contains(exception, "accessdenied") ||
+		strings.Contains(exception, "authentication") ||
+		strings.Contains(exception, "authorization") ||
+		strings.Contains(exception, "unauthorized") ||
+		strings.Contains(exception, "forbidden"):
+		return CategoryAuthorization
+	case strings.Contains(exception, "validation") ||
+		strings.Contains(exception, "input"):
+		return CategoryValidation
+	default:
+		return CategoryUnknown
+	}
+}
+
+// GraphQLErrors is the list of errors returned at the top level of a GraphQL response
+type GraphQLErrors []GraphQLError
+
+func (list GraphQLErrors) Error() string {
+	return formatErrors(list)
+}
+
+// Unwrap gives access to the individual errors, for use with errors.As
+func (list GraphQLErrors) Unwrap() []error {
+	return unwrapErrors(list)
+}
+
+// PayloadErrors is the list of errors returned in the payload of a mutation
+type PayloadErrors []GraphQLError
+
+func (list PayloadErrors) Error() string {
+	return formatErrors(list)
+}
+
+// Unwrap gives access to the individual errors, for use with errors.As
+func (list PayloadErrors) Unwrap() []error {
+	return unwrapErrors(list)
+}
+
+// HasCategory checks if the error, or any of the errors it wraps, is a GraphQL
+// error of the given category
+func HasCategory(err error, category ErrorCategory) bool {
+	switch e := err.(type) {
+	case nil:
+		return false
+	case *GraphQLError:
+		return e.Category() == category
+	case interface{ Unwrap() []error }:
+		for _, item := range e.Unwrap() {
+			if HasCategory(item, category) {
+				return true
+			}
+		}
+		return false
+	default:
+		return HasCategory(errors.Unwrap(err), category)
+	}
+}
+
+// IsNotFound checks if the error is about an entity which does not exist
+func IsNotFound(err error) bool {
+	var notFoundError *NotFoundError
+	return errors.As(err, &notFoundError) || HasCategory(err, CategoryNotFound)
+}
+
+// IsAlreadyExists checks if the error is about an entity which already exists
+func IsAlreadyExists(err error) bool {
+	return HasCategory(err, CategoryAlreadyExists)
+}
+
+// IsAuthorization checks if the error is about missing authentication or rights
+func IsAuthorization(err error) bool {
+	var authenticationError *AuthenticationError
+	return errors.As(err, &authenticationError) || HasCategory(err, CategoryAuthorization)
+}
+
+// IsValidation checks if the error is about some input rejected by Ontrack
+func IsValidation(err error) bool {
+	return HasCategory(err, CategoryValidation)
+}
+
+// NotFoundError is returned by the CLI itself when a lookup does not return any entity
+type NotFoundError struct {
+	Message string
+}
+
+func (e *NotFoundError) Error() string {
+	return e.Message
+}
+
+// NewNotFoundError creates a NotFoundError using a formatted message
+func NewNotFoundError(format string, args ...interface{}) error {
+	return &NotFoundError{Message: fmt.Sprintf(format, args...)}
+}
+
+// AuthenticationError is returned by the CLI itself when Ontrack does not recognize the credentials
+type AuthenticationError struct {
+	Message string
+}
+
+func (e *AuthenticationError) Error() string {
+	return e.Message
+}
+
+// HTTPError is returned when Ontrack answers with an HTTP error status
+type HTTPError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *HTTPError) Error() string {
+	return e.Message
+}
+
+func formatErrors(list []GraphQLError) string {
+	var message string
+	for index, error := range list {
+		message += fmt.Sprintf("%d) %s\n", index+1, error.Message)
+	}
+	return message
+}
+
+func unwrapErrors(list []GraphQLError) []error {
+	result := make([]error, 0, len(list))
+	for index := range list {
+		result = append(result, &list[index])
+	}
+	return result
+}
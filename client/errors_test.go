@@ -0,0 +1,108 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"yontrack/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphQLError_Category(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      GraphQLError
+		expected ErrorCategory
+	}{
+		{
+			name:     "no exception",
+			err:      GraphQLError{Message: "Something went wrong"},
+			expected: CategoryUnknown,
+		},
+		{
+			name:     "project not found",
+			err:      GraphQLError{Exception: "net.nemerosa.ontrack.model.exceptions.ProjectNotFoundException"},
+			expected: CategoryNotFound,
+		},
+		{
+			name:     "name already defined",
+			err:      GraphQLError{Exception: "net.nemerosa.ontrack.model.exceptions.ProjectNameAlreadyDefinedException"},
+			expected: CategoryAlreadyExists,
+		},
+		{
+			name:     "access denied",
+			err:      GraphQLError{Exception: "org.springframework.security.access.AccessDeniedException"},
+			expected: CategoryAuthorization,
+		},
+		{
+			name:     "input validation",
+			err:      GraphQLError{Exception: "net.nemerosa.ontrack.model.exceptions.InputException"},
+			expected: CategoryValidation,
+		},
+		{
+			name:     "classification in extensions",
+			err:      GraphQLError{Extensions: map[string]interface{}{"classification": "ValidationError"}},
+			expected: CategoryValidation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.err.Category())
+		})
+	}
+}
+
+func TestPayloadErrors(t *testing.T) {
+	err := CheckDataErrors([]GraphQLError{
+		{Message: "First", Exception: "net.nemerosa.ontrack.model.exceptions.InputException"},
+		{Message: "Second", Exception: "net.nemerosa.ontrack.model.exceptions.BuildNotFoundException"},
+	})
+	require.Error(t, err)
+	assert.Equal(t, "1) First\n2) Second\n", err.Error())
+
+	var payloadErrors PayloadErrors
+	require.True(t, errors.As(err, &payloadErrors))
+	assert.Len(t, payloadErrors, 2)
+
+	var graphQLError *GraphQLError
+	require.True(t, errors.As(err, &graphQLError))
+	assert.Equal(t, "First", graphQLError.Message)
+
+	// All the errors are checked, not only the first one
+	assert.True(t, IsValidation(err))
+	assert.True(t, IsNotFound(fmt.Errorf("wrapped: %w", err)))
+	assert.False(t, IsAuthorization(err))
+}
+
+func TestCheckDataErrors_None(t *testing.T) {
+	assert.NoError(t, CheckDataErrors(nil))
+	assert.NoError(t, CheckDataErrors([]GraphQLError{}))
+}
+
+func TestClient_GraphQLErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{
+			"data": null,
+			"errors": [{
+				"message": "Project not found: test",
+				"path": ["project", 0],
+				"extensions": {"exception": "net.nemerosa.ontrack.model.exceptions.ProjectNotFoundException"}
+			}]
+		}`))
+	}))
+	defer server.Close()
+
+	var data interface{}
+	err := New(&config.Config{URL: server.URL}).GraphQL("{ project { name } }", nil, &data)
+	require.Error(t, err)
+
+	var graphQLErrors GraphQLErrors
+	require.True(t, errors.As(err, &graphQLErrors))
+	require.Len(t, graphQLErrors, 1)
+	assert.Equal(t, []interface{}{"project", float64(0)}, graphQLErrors[0].Path)
+	assert.True(t, IsNotFound(err))
+}
@@ -2,19 +2,21 @@ package client
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
-	resty "github.com/go-resty/resty/v2"
-	"net/http"
-	"time"
 	config "yontrack/config"
 )
 
-// GraphQLCall performs a GraphQL query/mutation to Ontrack
+// GraphQLCall performs a GraphQL query/mutation to Ontrack, using the client shared
+// for this configuration
 func GraphQLCall(cfg *config.Config, query string, variables map[string]interface{}, data interface{}) error {
+	return ForConfig(cfg).GraphQL(query, variables, data)
+}
+
+// GraphQL performs a GraphQL query/mutation to Ontrack
+func (c *Client) GraphQL(query string, variables map[string]interface{}, data interface{}) error {
 
 	// If config is disabled, skips the call
-	if cfg.Disabled {
+	if c.cfg.Disabled {
 		return nil
 	}
 
@@ -23,32 +25,19 @@ func GraphQLCall(cfg *config.Config, query string, variables map[string]interfac
 		"variables": variables,
 	}
 
-	client := resty.New()
-	client.SetDebug(config.GraphQLLogging)
-	if cfg.Token != "" {
-		client.SetHeader("X-Ontrack-Token", cfg.Token)
-	} else if cfg.Username != "" {
-		client.SetBasicAuth(cfg.Username, cfg.Password)
-	}
-
-	if cfg.ConnectionRetry.MaxCount != 0 {
-		client.SetRetryCount(cfg.ConnectionRetry.MaxCount).
-			SetRetryMaxWaitTime(time.Duration(cfg.ConnectionRetry.MaxWaitTimeSec) * time.Second).
-			AddRetryCondition(func(r *resty.Response, err error) bool {
-				return err != nil || r.StatusCode() == http.StatusRequestTimeout || r.StatusCode() >= 500
-			})
-	}
-
-	resp, err := client.R().
+	resp, err := c.http.R().
 		SetHeader("Content-Type", "application/json").
 		SetBody(body).
-		Post(cfg.URL + "/graphql")
+		Post("/graphql")
 	if err != nil {
 		return err
 	}
 
 	if resp.IsError() {
-		return fmt.Errorf("%s:\n%s", resp.Status(), resp.Body())
+		return &HTTPError{
+			StatusCode: resp.StatusCode(),
+			Message:    fmt.Sprintf("%s:\n%s", resp.Status(), resp.Body()),
+		}
 	}
 
 	// Error returned
@@ -58,7 +47,10 @@ func GraphQLCall(cfg *config.Config, query string, variables map[string]interfac
 	}
 	if err := json.Unmarshal(resp.Body(), &error); err == nil {
 		if error.Status != 0 {
-			return fmt.Errorf("HTTP %d %s", error.Status, error.Message)
+			return &HTTPError{
+				StatusCode: error.Status,
+				Message:    fmt.Sprintf("HTTP %d %s", error.Status, error.Message),
+			}
 		}
 	}
 
@@ -71,41 +63,25 @@ func GraphQLCall(cfg *config.Config, query string, variables map[string]interfac
 	}
 
 	// Management of errors
-	if result.Errors != nil {
-		if len(result.Errors) > 0 {
-			var message string
-			for index, error := range result.Errors {
-				message += fmt.Sprintf("%d) %s\n", index+1, error.Message)
-			}
-			return errors.New(message)
-		}
+	if len(result.Errors) > 0 {
+		return result.Errors
 	}
 
 	// OK
 	return nil
 }
 
-type graphErr struct {
-	Message string
-}
-
 type graphResponse struct {
 	Data   interface{}
-	Errors []graphErr
+	Errors GraphQLErrors
 }
 
 // CheckDataErrors Given a list of errors in a data GraphQL structure (typically
-// returned by a mutation), returns a GoLang error aggregating all error messages
-// or returns nil if there is no error.
-func CheckDataErrors(errorsList []struct{ Message string }) error {
-	if errorsList != nil {
-		if len(errorsList) > 0 {
-			var message string
-			for index, error := range errorsList {
-				message += fmt.Sprintf("%d) %s\n", index+1, error.Message)
-			}
-			return errors.New(message)
-		}
+// returned by a mutation), returns a PayloadErrors error keeping all the details
+// of the errors or returns nil if there is no error.
+func CheckDataErrors(errorsList []GraphQLError) error {
+	if len(errorsList) > 0 {
+		return PayloadErrors(errorsList)
 	}
 	// All good
 	return nil
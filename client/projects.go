@@ -0,0 +1,180 @@
+package client
+
+import (
+	"strconv"
+	"yontrack/config"
+)
+
+// Project is the representation of an Ontrack project
+type Project struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Disabled    bool      `json:"disabled"`
+	Creation    Signature `json:"creation"`
+}
+
+// Signature identifies when and by whom an entity was created
+type Signature struct {
+	Time string `json:"time"`
+	User string `json:"user"`
+}
+
+// IntID returns the ID of the project as an integer, as expected by the mutations
+func (p *Project) IntID() (int, error) {
+	return strconv.Atoi(p.ID)
+}
+
+const projectFields = `
+	id
+	name
+	description
+	disabled
+	creation {
+		time
+		user
+	}
+`
+
+// GetProjectByName loads a project using its name, returning a NotFoundError if it does not exist
+func GetProjectByName(cfg *config.Config, name string) (*Project, error) {
+	var data struct {
+		Projects []Project
+	}
+	if err := GraphQLCall(cfg, `
+		query GetProjectByName($name: String!) {
+			projects(name: $name) {
+				`+projectFields+`
+			}
+		}
+	`, map[string]interface{}{
+		"name": name,
+	}, &data); err != nil {
+		return nil, err
+	}
+	if len(data.Projects) == 0 {
+		return nil, NewNotFoundError("project %s not found", name)
+	}
+	return &data.Projects[0], nil
+}
+
+// CreateProject creates a new project
+func CreateProject(cfg *config.Config, name string, description string, disabled bool) (*Project, error) {
+	var data struct {
+		CreateProject struct {
+			Project Project
+			Errors  []GraphQLError
+		}
+	}
+	if err := GraphQLCall(cfg, `
+		mutation CreateProject($name: String!, $description: String, $disabled: Boolean) {
+			createProject(input: {name: $name, description: $description, disabled: $disabled}) {
+				project {
+					`+projectFields+`
+				}
+				errors {
+					message
+					exception
+					location
+				}
+			}
+		}
+	`, map[string]interface{}{
+		"name":        name,
+		"description": description,
+		"disabled":    disabled,
+	}, &data); err != nil {
+		return nil, err
+	}
+	if err := CheckDataErrors(data.CreateProject.Errors); err != nil {
+		return nil, err
+	}
+	return &data.CreateProject.Project, nil
+}
+
+// UpdateProject updates the name and/or the description of a project.
+// nil values are left unchanged.
+func UpdateProject(cfg *config.Config, id int, name *string, description *string) (*Project, error) {
+	var data struct {
+		UpdateProject struct {
+			Project Project
+			Errors  []GraphQLError
+		}
+	}
+	if err := GraphQLCall(cfg, `
+		mutation UpdateProject($id: Int!, $name: String, $description: String) {
+			updateProject(input: {id: $id, name: $name, description: $description}) {
+				project {
+					`+projectFields+`
+				}
+				errors {
+					message
+					exception
+					location
+				}
+			}
+		}
+	`, map[string]interface{}{
+		"id":          id,
+		"name":        name,
+		"description": description,
+	}, &data); err != nil {
+		return nil, err
+	}
+	if err := CheckDataErrors(data.UpdateProject.Errors); err != nil {
+		return nil, err
+	}
+	return &data.UpdateProject.Project, nil
+}
+
+// SetProjectDisabled disables or enables a project
+func SetProjectDisabled(cfg *config.Config, id int, disabled bool) error {
+	var data map[string]struct {
+		Errors []GraphQLError
+	}
+	mutation := "enableProject"
+	if disabled {
+		mutation = "disableProject"
+	}
+	if err := GraphQLCall(cfg, `
+		mutation SetProjectState($id: Int!) {
+			`+mutation+`(input: {id: $id}) {
+				errors {
+					message
+					exception
+					location
+				}
+			}
+		}
+	`, map[string]interface{}{
+		"id": id,
+	}, &data); err != nil {
+		return err
+	}
+	return CheckDataErrors(data[mutation].Errors)
+}
+
+// DeleteProject deletes a project and all its content
+func DeleteProject(cfg *config.Config, id int) error {
+	var data struct {
+		DeleteProject struct {
+			Errors []GraphQLError
+		}
+	}
+	if err := GraphQLCall(cfg, `
+		mutation DeleteProject($id: Int!) {
+			deleteProject(input: {id: $id}) {
+				errors {
+					message
+					exception
+					location
+				}
+			}
+		}
+	`, map[string]interface{}{
+		"id": id,
+	}, &data); err != nil {
+		return err
+	}
+	return CheckDataErrors(data.DeleteProject.Errors)
+}
@@ -0,0 +1,163 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"yontrack/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// graphQLRequest is the body of a GraphQL call received by the test servers
+type graphQLRequest struct {
+	Query     string
+	Variables map[string]interface{}
+}
+
+// newProjectTestServer returns a server answering all the GraphQL calls with the given response,
+// and recording the last call into request
+func newProjectTestServer(t *testing.T, request *graphQLRequest, response string) *config.Config {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(request))
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return &config.Config{URL: server.URL}
+}
+
+const projectTestPayloadErrors = `"errors":[{
+	"message":"Project name already exists: test",
+	"exception":"net.nemerosa.ontrack.model.exceptions.ProjectNameAlreadyDefinedException"
+}]`
+
+// requirePayloadErrors checks that the error is made of the errors of the mutation payload
+func requirePayloadErrors(t *testing.T, err error) {
+	require.Error(t, err)
+	var payloadErrors PayloadErrors
+	require.True(t, errors.As(err, &payloadErrors))
+	assert.Equal(t, "Project name already exists: test", payloadErrors[0].Message)
+	assert.True(t, IsAlreadyExists(err))
+}
+
+func TestGetProjectByName(t *testing.T) {
+	var request graphQLRequest
+	cfg := newProjectTestServer(t, &request, `{"data":{"projects":[{
+		"id":"12","name":"test","description":"Test","disabled":true,
+		"creation":{"time":"2024-01-01T10:00:00Z","user":"admin"}
+	}]}}`)
+
+	project, err := GetProjectByName(cfg, "test")
+	require.NoError(t, err)
+	assert.Equal(t, "test", request.Variables["name"])
+	assert.Equal(t, &Project{
+		ID:          "12",
+		Name:        "test",
+		Description: "Test",
+		Disabled:    true,
+		Creation:    Signature{Time: "2024-01-01T10:00:00Z", User: "admin"},
+	}, project)
+}
+
+func TestGetProjectByName_NotFound(t *testing.T) {
+	var request graphQLRequest
+	cfg := newProjectTestServer(t, &request, `{"data":{"projects":[]}}`)
+
+	_, err := GetProjectByName(cfg, "test")
+	require.Error(t, err)
+	assert.True(t, IsNotFound(err))
+}
+
+func TestCreateProject(t *testing.T) {
+	var request graphQLRequest
+	cfg := newProjectTestServer(t, &request, `{"data":{"createProject":{
+		"project":{"id":"12","name":"test","description":"Test","disabled":false},
+		"errors":[]
+	}}}`)
+
+	project, err := CreateProject(cfg, "test", "Test", false)
+	require.NoError(t, err)
+	assert.Contains(t, request.Query, "createProject(")
+	assert.Equal(t, map[string]interface{}{"name": "test", "description": "Test", "disabled": false}, request.Variables)
+	assert.Equal(t, "12", project.ID)
+	assert.Equal(t, "test", project.Name)
+}
+
+func TestCreateProject_PayloadErrors(t *testing.T) {
+	var request graphQLRequest
+	cfg := newProjectTestServer(t, &request, `{"data":{"createProject":{"project":null,`+projectTestPayloadErrors+`}}}`)
+
+	_, err := CreateProject(cfg, "test", "", false)
+	requirePayloadErrors(t, err)
+}
+
+func TestUpdateProject(t *testing.T) {
+	var request graphQLRequest
+	cfg := newProjectTestServer(t, &request, `{"data":{"updateProject":{
+		"project":{"id":"12","name":"test","description":"New","disabled":false},
+		"errors":null
+	}}}`)
+
+	description := "New"
+	project, err := UpdateProject(cfg, 12, nil, &description)
+	require.NoError(t, err)
+	assert.Contains(t, request.Query, "updateProject(")
+	// The name is left unchanged
+	assert.Equal(t, map[string]interface{}{"id": float64(12), "name": nil, "description": "New"}, request.Variables)
+	assert.Equal(t, "New", project.Description)
+}
+
+func TestUpdateProject_PayloadErrors(t *testing.T) {
+	var request graphQLRequest
+	cfg := newProjectTestServer(t, &request, `{"data":{"updateProject":{"project":null,`+projectTestPayloadErrors+`}}}`)
+
+	name := "test"
+	_, err := UpdateProject(cfg, 12, &name, nil)
+	requirePayloadErrors(t, err)
+}
+
+func TestSetProjectDisabled(t *testing.T) {
+	tests := []struct {
+		disabled bool
+		mutation string
+	}{
+		{true, "disableProject"},
+		{false, "enableProject"},
+	}
+	for _, test := range tests {
+		t.Run(test.mutation, func(t *testing.T) {
+			var request graphQLRequest
+			cfg := newProjectTestServer(t, &request, `{"data":{"`+test.mutation+`":{"errors":[]}}}`)
+
+			require.NoError(t, SetProjectDisabled(cfg, 12, test.disabled))
+			assert.Contains(t, request.Query, test.mutation+"(")
+			assert.Equal(t, float64(12), request.Variables["id"])
+		})
+	}
+}
+
+func TestSetProjectDisabled_PayloadErrors(t *testing.T) {
+	var request graphQLRequest
+	cfg := newProjectTestServer(t, &request, `{"data":{"disableProject":{`+projectTestPayloadErrors+`}}}`)
+
+	requirePayloadErrors(t, SetProjectDisabled(cfg, 12, true))
+}
+
+func TestDeleteProject(t *testing.T) {
+	var request graphQLRequest
+	cfg := newProjectTestServer(t, &request, `{"data":{"deleteProject":{"errors":[]}}}`)
+
+	require.NoError(t, DeleteProject(cfg, 12))
+	assert.Contains(t, request.Query, "deleteProject(")
+	assert.Equal(t, float64(12), request.Variables["id"])
+}
+
+func TestDeleteProject_PayloadErrors(t *testing.T) {
+	var request graphQLRequest
+	cfg := newProjectTestServer(t, &request, `{"data":{"deleteProject":{`+projectTestPayloadErrors+`}}}`)
+
+	requirePayloadErrors(t, DeleteProject(cfg, 12))
+}
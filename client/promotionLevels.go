@@ -18,14 +18,10 @@ func SetupPromotionLevel(
 	// Data
 	var data struct {
 		SetupPromotionLevel struct {
-			Errors []struct {
-				Message string
-			}
+			Errors []GraphQLError
 		}
 		SetPromotionLevelAutoPromotionProperty struct {
-			Errors []struct {
-				Message string
-			}
+			Errors []GraphQLError
 		}
 	}
 
@@ -50,6 +46,8 @@ func SetupPromotionLevel(
 				}) {
 					errors {
 						message
+						exception
+						location
 					}
 				}
 				setPromotionLevelAutoPromotionProperty(input: {
@@ -63,6 +61,8 @@ func SetupPromotionLevel(
 				}) @include(if: $autoPromotion) {
 					errors {
 						message
+						exception
+						location
 					}
 				}
 			}
@@ -106,9 +106,7 @@ func SubscribePromotionLevel(
 
 	var data struct {
 		SubscribePromotionLevelToEvents struct {
-			Errors []struct {
-				Message string
-			}
+			Errors []GraphQLError
 		}
 	}
 
@@ -135,6 +133,8 @@ func SubscribePromotionLevel(
 		  }) {
 			errors {
 			  message
+			  exception
+			  location
 			}
 		  }
 		}
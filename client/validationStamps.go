@@ -35,6 +35,8 @@ func SetupValidationStamp(
 			}) {
 				errors {
 					message
+					exception
+					location
 				}
 			}
 		}
@@ -60,9 +62,7 @@ func SetupValidationStamp(
 
 	var data struct {
 		SetupValidationStamp struct {
-			Errors []struct {
-				Message string
-			}
+			Errors []GraphQLError
 		}
 	}
 	if err := GraphQLCall(cfg, query.String(), map[string]interface{}{
@@ -21,9 +21,7 @@ func ValidateWithTests(
 	// Mutation payload
 	var payload struct {
 		ValidateBuildWithTests struct {
-			Errors []struct {
-				Message string
-			}
+			Errors []GraphQLError
 		}
 	}
 
@@ -55,6 +53,8 @@ func ValidateWithTests(
 				}) {
 					errors {
 						message
+						exception
+						location
 					}
 				}
 			}
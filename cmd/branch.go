@@ -40,6 +40,18 @@ a branch and its setup. For example:
 
     yontrack branch setup --project my-project --branch release/1.0
 
+The branches of a project can be listed, inspected, disabled, enabled or deleted:
+
+    yontrack branch list --project my-project --name 'feature-.*'
+    yontrack branch get --project my-project --branch main
+    yontrack branch disable --project my-project --branch feature/done
+    yontrack branch enable --project my-project --branch feature/done
+    yontrack branch delete --project my-project --branch feature/done
+
+Stale branches can be disabled or deleted in bulk:
+
+    yontrack branch cleanup --project my-project --name 'feature-.*' --inactive-days 30 --dry-run
+
 See 'yontrack branch' for a list of other options.
 `,
 	// Run: func(cmd *cobra.Command, args []string) {},
@@ -68,9 +68,7 @@ The format of this file is full described in the Ontrack documentation.
 
 		var data struct {
 			SetAutoVersioningConfigByName struct {
-				Errors []struct {
-					Message string
-				}
+				Errors []client.GraphQLError
 			}
 		}
 
@@ -88,6 +86,8 @@ The format of this file is full described in the Ontrack documentation.
 			  }) {
 				errors {
 				  message
+				  exception
+				  location
 				}
 			  }
 			}
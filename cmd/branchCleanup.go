@@ -0,0 +1,281 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+	"yontrack/client"
+	"yontrack/config"
+	"yontrack/output"
+	"yontrack/utils"
+
+	"github.com/spf13/cobra"
+)
+
+// Actions which can be performed by the branch cleanup
+const (
+	cleanupDisable = "disable"
+	cleanupDelete  = "delete"
+)
+
+var branchCleanupCmd = &cobra.Command{
+	Use:   "cleanup",
+	Short: "Disables or deletes the stale branches of a project",
+	Long: `Disables or deletes the stale branches of a project.
+
+Branches are selected using at least one of:
+
+  --name REGEX          regular expression on the branch names
+  --inactive-days DAYS  no build for at least DAYS days (the branch creation is used when there is no build)
+  --disabled            only the branches which are already disabled (requires --action delete)
+
+Branches matching any of the --keep patterns are never selected. These patterns are globs
+(like release/*) applied on the Ontrack branch names, Git branch names being normalized the
+same way as for the --branch flag. By default, main, master, develop and release/* are kept.
+
+The selected branches are disabled, or deleted when using --action delete. The plan is always
+displayed first and a confirmation is asked, unless --yes is used. Use --dry-run to only display
+the plan.
+
+	yontrack branch cleanup --project my-project --name 'feature-.*' --inactive-days 30 --dry-run
+	yontrack branch cleanup --project my-project --name 'feature-.*' --inactive-days 30 --yes
+	yontrack branch cleanup --project my-project --disabled --action delete --keep 'release/*' --keep 'hotfix/*'
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		printer, err := output.FromCommand(cmd)
+		if err != nil {
+			return err
+		}
+		project, err := utils.GetProjectFlag(cmd)
+		if err != nil {
+			return err
+		}
+		criteria, err := branchCleanupCriteriaFromCommand(cmd)
+		if err != nil {
+			return err
+		}
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return err
+		}
+		yes, err := cmd.Flags().GetBool("yes")
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.GetSelectedConfiguration()
+		if err != nil {
+			return err
+		}
+
+		filter := client.BranchFilter{Name: criteria.Name}
+		if criteria.DisabledOnly {
+			enabled := false
+			filter.Enabled = &enabled
+		}
+		branches, err := client.GetBranches(cfg, project, filter)
+		if err != nil {
+			return err
+		}
+
+		plan, err := planBranchCleanup(branches, criteria, time.Now())
+		if err != nil {
+			return err
+		}
+
+		rows := make([][]string, 0, len(plan))
+		for _, item := range plan {
+			rows = append(rows, []string{item.Branch, item.LastActivity, item.Action})
+		}
+		if err := printer.Print(&output.Result{
+			Value:   plan,
+			Headers: []string{"BRANCH", "LAST ACTIVITY", "ACTION"},
+			Rows:    rows,
+			Plain: func(w io.Writer) error {
+				for _, item := range plan {
+					_, _ = fmt.Fprintf(w, "%s %s (last activity: %s)\n", item.Action, item.Branch, item.LastActivity)
+				}
+				return nil
+			},
+		}); err != nil {
+			return err
+		}
+
+		if dryRun || len(plan) == 0 {
+			_, _ = fmt.Fprintf(os.Stderr, "%d branch(es) selected for cleanup in project %s\n", len(plan), project)
+			return nil
+		}
+		if !yes {
+			ok, err := utils.Confirm(os.Stdin, os.Stderr, fmt.Sprintf("Applying the cleanup on %d branch(es) of project %s. Continue?", len(plan), project))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return fmt.Errorf("cleanup of project %s cancelled", project)
+			}
+		}
+
+		for _, item := range plan {
+			var err error
+			if item.Action == cleanupDelete {
+				err = client.DeleteBranch(cfg, item.id)
+			} else {
+				err = client.SetBranchDisabled(cfg, item.id, true)
+			}
+			if err != nil {
+				return fmt.Errorf("cannot %s branch %s: %w", item.Action, item.Branch, err)
+			}
+		}
+		_, _ = fmt.Fprintf(os.Stderr, "%d branch(es) cleaned up in project %s\n", len(plan), project)
+		return nil
+	},
+}
+
+// branchCleanupCriteria defines which branches must be cleaned up and how
+type branchCleanupCriteria struct {
+	Name         string
+	InactiveDays int
+	DisabledOnly bool
+	Keep         []string
+	Action       string
+}
+
+// branchCleanupItem is a branch selected for cleanup
+type branchCleanupItem struct {
+	id           int
+	Branch       string `json:"branch"`
+	LastActivity string `json:"lastActivity"`
+	Action       string `json:"action"`
+}
+
+func branchCleanupCriteriaFromCommand(cmd *cobra.Command) (branchCleanupCriteria, error) {
+	var criteria branchCleanupCriteria
+	var err error
+	if criteria.Name, err = cmd.Flags().GetString("name"); err != nil {
+		return criteria, err
+	}
+	if criteria.InactiveDays, err = cmd.Flags().GetInt("inactive-days"); err != nil {
+		return criteria, err
+	}
+	if criteria.DisabledOnly, err = cmd.Flags().GetBool("disabled"); err != nil {
+		return criteria, err
+	}
+	if criteria.Keep, err = cmd.Flags().GetStringSlice("keep"); err != nil {
+		return criteria, err
+	}
+	if criteria.Action, err = cmd.Flags().GetString("action"); err != nil {
+		return criteria, err
+	}
+	if criteria.Action != cleanupDisable && criteria.Action != cleanupDelete {
+		return criteria, fmt.Errorf("unsupported action %s (expected %s or %s)", criteria.Action, cleanupDisable, cleanupDelete)
+	}
+	// Disabled branches are never disabled again
+	if criteria.DisabledOnly && criteria.Action != cleanupDelete {
+		return criteria, fmt.Errorf("--disabled selects branches which are already disabled and requires --action %s", cleanupDelete)
+	}
+	if criteria.InactiveDays < 0 {
+		return criteria, fmt.Errorf("--inactive-days must be positive")
+	}
+	if criteria.Name == "" && criteria.InactiveDays == 0 && !criteria.DisabledOnly {
+		return criteria, fmt.Errorf("at least one selection criterion is required (--name, --inactive-days or --disabled)")
+	}
+	if criteria.Name != "" {
+		if _, err := regexp.Compile(criteria.Name); err != nil {
+			return criteria, fmt.Errorf("invalid --name regular expression: %w", err)
+		}
+	}
+	for _, pattern := range criteria.Keep {
+		if _, err := path.Match(normalizeBranchPattern(pattern), ""); err != nil {
+			return criteria, fmt.Errorf("invalid --keep pattern %s: %w", pattern, err)
+		}
+	}
+	return criteria, nil
+}
+
+// planBranchCleanup selects the branches to clean up
+func planBranchCleanup(branches []client.Branch, criteria branchCleanupCriteria, now time.Time) ([]branchCleanupItem, error) {
+	plan := make([]branchCleanupItem, 0)
+	for _, branch := range branches {
+		if isKeptBranch(branch.Name, criteria.Keep) {
+			continue
+		}
+		if criteria.DisabledOnly && !branch.Disabled {
+			continue
+		}
+		// Nothing to do for a branch which is already disabled
+		if criteria.Action == cleanupDisable && branch.Disabled {
+			continue
+		}
+		lastActivity := branch.Creation.Time
+		if build := branch.LastBuild(); build != nil {
+			lastActivity = build.Creation.Time
+		}
+		if criteria.InactiveDays > 0 {
+			activity, err := time.Parse(time.RFC3339, lastActivity)
+			if err != nil {
+				return nil, fmt.Errorf("cannot get the last activity of branch %s: %w", branch.Name, err)
+			}
+			if now.Sub(activity) < time.Duration(criteria.InactiveDays)*24*time.Hour {
+				continue
+			}
+		}
+		id, err := branch.IntID()
+		if err != nil {
+			return nil, err
+		}
+		plan = append(plan, branchCleanupItem{
+			id:           id,
+			Branch:       branch.Name,
+			LastActivity: lastActivity,
+			Action:       criteria.Action,
+		})
+	}
+	return plan, nil
+}
+
+func isKeptBranch(name string, keep []string) bool {
+	for _, pattern := range keep {
+		if ok, _ := path.Match(normalizeBranchPattern(pattern), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeBranchPattern normalizes a glob on Git branch names so that it can be applied
+// on Ontrack branch names, keeping the wildcards.
+func normalizeBranchPattern(pattern string) string {
+	var result strings.Builder
+	literal := ""
+	for _, c := range pattern {
+		if c == '*' || c == '?' {
+			result.WriteString(utils.NormalizeBranchName(literal))
+			result.WriteRune(c)
+			literal = ""
+		} else {
+			literal += string(c)
+		}
+	}
+	result.WriteString(utils.NormalizeBranchName(literal))
+	return result.String()
+}
+
+func init() {
+	branchCmd.AddCommand(branchCleanupCmd)
+	registerBranchCleanupFlags(branchCleanupCmd)
+}
+
+func registerBranchCleanupFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("project", "p", "", "Project name")
+	cmd.Flags().StringP("name", "n", "", "Regular expression on the names of the branches to clean up")
+	cmd.Flags().Int("inactive-days", 0, "Selects only the branches without any build for this number of days")
+	cmd.Flags().Bool("disabled", false, "Selects only the disabled branches (requires --action delete)")
+	cmd.Flags().StringSlice("keep", []string{"main", "master", "develop", "release/*"}, "Patterns of the branches to never clean up")
+	cmd.Flags().String("action", cleanupDisable, "Action to perform on the selected branches: disable or delete")
+	cmd.Flags().Bool("dry-run", false, "Only displays the branches which would be cleaned up")
+	cmd.Flags().BoolP("yes", "y", false, "Does not ask for confirmation")
+}
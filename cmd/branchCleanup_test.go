@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+	"yontrack/client"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func cleanupTestBranch(id string, name string, disabled bool, lastBuild string) client.Branch {
+	branch := client.Branch{ID: id, Name: name, Disabled: disabled}
+	branch.Creation.Time = "2024-01-01T00:00:00Z"
+	if lastBuild != "" {
+		branch.Builds = []client.BranchBuild{{Name: "1", Creation: client.Signature{Time: lastBuild}}}
+	}
+	return branch
+}
+
+func TestPlanBranchCleanup(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	branches := []client.Branch{
+		cleanupTestBranch("1", "main", false, "2024-01-01T00:00:00Z"),
+		cleanupTestBranch("2", "release-1.0", false, "2024-01-01T00:00:00Z"),
+		cleanupTestBranch("3", "feature-old", false, "2024-03-01T00:00:00.123Z"),
+		cleanupTestBranch("4", "feature-recent", false, "2024-05-25T00:00:00Z"),
+		cleanupTestBranch("5", "feature-disabled", true, "2024-01-01T00:00:00Z"),
+		cleanupTestBranch("6", "feature-no-build", false, ""),
+	}
+
+	plan, err := planBranchCleanup(branches, branchCleanupCriteria{
+		InactiveDays: 30,
+		Keep:         []string{"main", "release/*"},
+		Action:       cleanupDisable,
+	}, now)
+	require.NoError(t, err)
+	assert.Equal(t, []branchCleanupItem{
+		{id: 3, Branch: "feature-old", LastActivity: "2024-03-01T00:00:00.123Z", Action: cleanupDisable},
+		{id: 6, Branch: "feature-no-build", LastActivity: "2024-01-01T00:00:00Z", Action: cleanupDisable},
+	}, plan)
+
+	plan, err = planBranchCleanup(branches, branchCleanupCriteria{
+		DisabledOnly: true,
+		Action:       cleanupDelete,
+	}, now)
+	require.NoError(t, err)
+	assert.Equal(t, []branchCleanupItem{
+		{id: 5, Branch: "feature-disabled", LastActivity: "2024-01-01T00:00:00Z", Action: cleanupDelete},
+	}, plan)
+}
+
+func TestNormalizeBranchPattern(t *testing.T) {
+	assert.Equal(t, "release-*", normalizeBranchPattern("release/*"))
+	assert.Equal(t, "feature-?-x", normalizeBranchPattern("feature/?/x"))
+	assert.Equal(t, "main", normalizeBranchPattern("main"))
+	assert.True(t, isKeptBranch("release-1.0", []string{"release/*"}))
+	assert.False(t, isKeptBranch("feature-release-1.0", []string{"release/*"}))
+}
+
+func TestBranchCleanupCriteriaFromCommand(t *testing.T) {
+	parse := func(args ...string) (branchCleanupCriteria, error) {
+		return branchCleanupCriteriaFromCommand(newTestCommand(t, registerBranchCleanupFlags, args...))
+	}
+
+	criteria, err := parse("--inactive-days", "30")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"main", "master", "develop", "release/*"}, criteria.Keep)
+	assert.Equal(t, cleanupDisable, criteria.Action)
+
+	criteria, err = parse("--disabled", "--keep", "hotfix/*", "--action", "delete")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"hotfix/*"}, criteria.Keep)
+	assert.Equal(t, cleanupDelete, criteria.Action)
+
+	_, err = parse()
+	assert.EqualError(t, err, "at least one selection criterion is required (--name, --inactive-days or --disabled)")
+
+	_, err = parse("--disabled")
+	assert.EqualError(t, err, "--disabled selects branches which are already disabled and requires --action delete")
+
+	_, err = parse("--disabled", "--name", "feature-.*", "--action", "disable")
+	assert.Error(t, err)
+
+	_, err = parse("--keep", "hotfix/*", "--action", "delete")
+	assert.Error(t, err, "--keep is not a selection criterion")
+
+	_, err = parse("--name", ".*", "--action", "archive")
+	assert.EqualError(t, err, "unsupported action archive (expected disable or delete)")
+
+	_, err = parse("--name", "feature-(")
+	assert.Error(t, err)
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"yontrack/client"
+	"yontrack/utils"
+
+	"github.com/spf13/cobra"
+)
+
+var branchDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Deletes a branch",
+	Long: `Deletes a branch, with all its builds.
+
+A confirmation is asked before the deletion, unless --yes is used:
+
+	yontrack branch delete --project my-project --branch feature/done
+	yontrack branch delete --project my-project --branch feature/done --yes
+
+--project defaults to YONTRACK_PROJECT_NAME and --branch defaults to YONTRACK_BRANCH_NAME.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		yes, err := cmd.Flags().GetBool("yes")
+		if err != nil {
+			return err
+		}
+		cfg, branch, id, err := loadBranch(cmd, 1)
+		if err != nil {
+			return err
+		}
+		if !yes {
+			ok, err := utils.Confirm(os.Stdin, os.Stderr, fmt.Sprintf("Deleting branch %s/%s and all its builds. Continue?", branch.Project.Name, branch.Name))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return fmt.Errorf("deletion of branch %s/%s cancelled", branch.Project.Name, branch.Name)
+			}
+		}
+		return client.DeleteBranch(cfg, id)
+	},
+}
+
+func init() {
+	branchCmd.AddCommand(branchDeleteCmd)
+
+	branchDeleteCmd.Flags().StringP("project", "p", "", "Project name")
+	branchDeleteCmd.Flags().StringP("branch", "b", "", "Branch name or Git branch name")
+	branchDeleteCmd.Flags().BoolP("yes", "y", false, "Does not ask for confirmation")
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"yontrack/client"
+
+	"github.com/spf13/cobra"
+)
+
+var branchDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Disables a branch",
+	Long: `Disables a branch.
+
+	yontrack branch disable --project my-project --branch feature/done
+
+--project defaults to YONTRACK_PROJECT_NAME and --branch defaults to YONTRACK_BRANCH_NAME.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, _, id, err := loadBranch(cmd, 1)
+		if err != nil {
+			return err
+		}
+		return client.SetBranchDisabled(cfg, id, true)
+	},
+}
+
+func init() {
+	branchCmd.AddCommand(branchDisableCmd)
+
+	branchDisableCmd.Flags().StringP("project", "p", "", "Project name")
+	branchDisableCmd.Flags().StringP("branch", "b", "", "Branch name or Git branch name")
+}
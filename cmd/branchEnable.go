@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"yontrack/client"
+
+	"github.com/spf13/cobra"
+)
+
+var branchEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Enables a branch",
+	Long: `Enables a branch.
+
+	yontrack branch enable --project my-project --branch feature/done
+
+--project defaults to YONTRACK_PROJECT_NAME and --branch defaults to YONTRACK_BRANCH_NAME.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, _, id, err := loadBranch(cmd, 1)
+		if err != nil {
+			return err
+		}
+		return client.SetBranchDisabled(cfg, id, false)
+	},
+}
+
+func init() {
+	branchCmd.AddCommand(branchEnableCmd)
+
+	branchEnableCmd.Flags().StringP("project", "p", "", "Project name")
+	branchEnableCmd.Flags().StringP("branch", "b", "", "Branch name or Git branch name")
+}
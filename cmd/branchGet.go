@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"yontrack/client"
+	"yontrack/config"
+	"yontrack/output"
+	"yontrack/utils"
+
+	"github.com/spf13/cobra"
+)
+
+var branchGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Displays a branch",
+	Long: `Displays a branch, with its promotion levels and its last builds.
+
+	yontrack branch get --project my-project --branch main
+	yontrack branch get --project my-project --branch main --builds 10 --output json
+
+--project defaults to YONTRACK_PROJECT_NAME and --branch defaults to YONTRACK_BRANCH_NAME.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		printer, err := output.FromCommand(cmd)
+		if err != nil {
+			return err
+		}
+		builds, err := cmd.Flags().GetInt("builds")
+		if err != nil {
+			return err
+		}
+		_, branch, _, err := loadBranch(cmd, builds)
+		if err != nil {
+			return err
+		}
+
+		return printer.Print(&output.Result{
+			Value:   branch,
+			Headers: []string{"ID", "NAME", "DISABLED", "LAST BUILD", "LAST BUILD TIME"},
+			Rows:    [][]string{branchRow(*branch)},
+			Env:     branchEnvVariables(branch.Project.ID, branch.Project.Name, branch.ID, branch.Name),
+			Plain: func(w io.Writer) error {
+				_, _ = fmt.Fprintf(w, "ID:          %s\n", branch.ID)
+				_, _ = fmt.Fprintf(w, "Project:     %s\n", branch.Project.Name)
+				_, _ = fmt.Fprintf(w, "Name:        %s\n", branch.Name)
+				_, _ = fmt.Fprintf(w, "Description: %s\n", branch.Description)
+				_, _ = fmt.Fprintf(w, "Disabled:    %t\n", branch.Disabled)
+				_, _ = fmt.Fprintf(w, "Created:     %s by %s\n", branch.Creation.Time, branch.Creation.User)
+				_, _ = fmt.Fprintln(w, "Promotion levels:")
+				for _, promotionLevel := range branch.PromotionLevels {
+					_, _ = fmt.Fprintf(w, "  %s\n", promotionLevel.Name)
+				}
+				_, _ = fmt.Fprintln(w, "Last builds:")
+				for _, build := range branch.Builds {
+					_, _ = fmt.Fprintf(w, "  %s  %s  %s\n", build.Name, build.Creation.Time, strings.Join(build.Promotions(), ","))
+				}
+				return nil
+			},
+		})
+	},
+}
+
+// loadBranch loads the branch identified by the --project and --branch flags,
+// returning the configuration used to load it and its numeric ID
+func loadBranch(cmd *cobra.Command, builds int) (*config.Config, *client.Branch, int, error) {
+	project, branchName, err := utils.GetProjectBranchFlags(cmd, false, true)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	cfg, err := config.GetSelectedConfiguration()
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	branch, err := client.GetBranchByName(cfg, project, branchName, builds)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	id, err := branch.IntID()
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return cfg, branch, id, nil
+}
+
+func init() {
+	branchCmd.AddCommand(branchGetCmd)
+
+	branchGetCmd.Flags().StringP("project", "p", "", "Project name")
+	branchGetCmd.Flags().StringP("branch", "b", "", "Branch name or Git branch name")
+	branchGetCmd.Flags().Int("builds", 5, "Number of last builds to display")
+}
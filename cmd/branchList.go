@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"yontrack/client"
+	"yontrack/config"
+	"yontrack/output"
+	"yontrack/utils"
+
+	"github.com/spf13/cobra"
+)
+
+var branchListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Displays the list of branches of a project",
+	Long: `Displays the list of branches of a project, from the most recent activity.
+
+	yontrack branch list --project my-project
+
+The list can be filtered on the branch name (using a regular expression),
+on the disabled state and on the number of branches:
+
+	yontrack branch list --project my-project --name 'feature-.*' --disabled
+	yontrack branch list --project my-project --enabled --count 10
+
+By default, only the names are displayed. Use --output to get more information:
+
+	yontrack branch list --project my-project --output table
+	yontrack branch list --project my-project --output json
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		printer, err := output.FromCommand(cmd)
+		if err != nil {
+			return err
+		}
+		project, err := utils.GetProjectFlag(cmd)
+		if err != nil {
+			return err
+		}
+		filter, err := branchFilter(cmd)
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.GetSelectedConfiguration()
+		if err != nil {
+			return err
+		}
+		branches, err := client.GetBranches(cfg, project, filter)
+		if err != nil {
+			return err
+		}
+
+		rows := make([][]string, 0, len(branches))
+		for _, branch := range branches {
+			rows = append(rows, branchRow(branch))
+		}
+		return printer.Print(&output.Result{
+			Value:   branches,
+			Headers: []string{"ID", "NAME", "DISABLED", "LAST BUILD", "LAST BUILD TIME"},
+			Rows:    rows,
+			Plain: func(w io.Writer) error {
+				for _, branch := range branches {
+					_, _ = fmt.Fprintln(w, branch.Name)
+				}
+				return nil
+			},
+		})
+	},
+}
+
+// branchFilter gets the filter on branches from the command flags
+func branchFilter(cmd *cobra.Command) (client.BranchFilter, error) {
+	var filter client.BranchFilter
+	var err error
+	if filter.Name, err = cmd.Flags().GetString("name"); err != nil {
+		return filter, err
+	}
+	if filter.Count, err = cmd.Flags().GetInt("count"); err != nil {
+		return filter, err
+	}
+	enabled, err := cmd.Flags().GetBool("enabled")
+	if err != nil {
+		return filter, err
+	}
+	disabled, err := cmd.Flags().GetBool("disabled")
+	if err != nil {
+		return filter, err
+	}
+	if enabled && disabled {
+		return filter, errors.New("--enabled and --disabled cannot be used together")
+	}
+	if enabled || disabled {
+		filter.Enabled = &enabled
+	}
+	return filter, nil
+}
+
+func branchRow(branch client.Branch) []string {
+	lastBuild, lastBuildTime := "", ""
+	if build := branch.LastBuild(); build != nil {
+		lastBuild = build.Name
+		lastBuildTime = build.Creation.Time
+	}
+	return []string{branch.ID, branch.Name, fmt.Sprint(branch.Disabled), lastBuild, lastBuildTime}
+}
+
+func init() {
+	branchCmd.AddCommand(branchListCmd)
+	registerBranchListFlags(branchListCmd)
+}
+
+func registerBranchListFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("project", "p", "", "Project name")
+	cmd.Flags().StringP("name", "n", "", "Regular expression on the branch names")
+	cmd.Flags().Bool("enabled", false, "Lists only the enabled branches")
+	cmd.Flags().Bool("disabled", false, "Lists only the disabled branches")
+	cmd.Flags().IntP("count", "c", 0, "Maximum number of branches to return")
+}
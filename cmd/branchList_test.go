@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBranchFilter(t *testing.T) {
+	filter, err := branchFilter(newTestCommand(t, registerBranchListFlags, "--name", "feature-.*", "--count", "10"))
+	require.NoError(t, err)
+	assert.Equal(t, "feature-.*", filter.Name)
+	assert.Equal(t, 10, filter.Count)
+	assert.Nil(t, filter.Enabled)
+
+	filter, err = branchFilter(newTestCommand(t, registerBranchListFlags, "--disabled"))
+	require.NoError(t, err)
+	require.NotNil(t, filter.Enabled)
+	assert.False(t, *filter.Enabled)
+
+	filter, err = branchFilter(newTestCommand(t, registerBranchListFlags, "--enabled"))
+	require.NoError(t, err)
+	require.NotNil(t, filter.Enabled)
+	assert.True(t, *filter.Enabled)
+
+	_, err = branchFilter(newTestCommand(t, registerBranchListFlags, "--enabled", "--disabled"))
+	assert.EqualError(t, err, "--enabled and --disabled cannot be used together")
+}
@@ -64,9 +64,7 @@ As of now, this also sets the "GitCommitPropertyLink" property by default (build
 
 		var data struct {
 			SetBranchGitConfigProperty struct {
-				Errors []struct {
-					Message string
-				}
+				Errors []client.GraphQLError
 			}
 		}
 		if err := client.GraphQLCall(cfg, `
@@ -82,6 +80,8 @@ As of now, this also sets the "GitCommitPropertyLink" property by default (build
 				}) {
 					errors {
 						message
+						exception
+						location
 					}
 				}
 			}
@@ -77,27 +77,19 @@ can directly give the name of the Git branch.
 				Project struct {
 					ID int
 				}
-				Errors []struct {
-					Message string
-				}
+				Errors []client.GraphQLError
 			}
 			CreateBranchOrGet struct {
 				Branch struct {
 					ID int
 				}
-				Errors []struct {
-					Message string
-				}
+				Errors []client.GraphQLError
 			}
 			SetProjectAutoValidationStampProperty struct {
-				Errors []struct {
-					Message string
-				}
+				Errors []client.GraphQLError
 			}
 			SetProjectAutoPromotionLevelProperty struct {
-				Errors []struct {
-					Message string
-				}
+				Errors []client.GraphQLError
 			}
 		}
 		if err := client.GraphQLCall(config, `
@@ -111,11 +103,15 @@ can directly give the name of the Git branch.
 				createProjectOrGet(input: {name: $project}) {
 					errors {
 					message
+					exception
+					location
 					}
 				}
 				createBranchOrGet(input: {projectName: $project, name: $branch}) {
 					errors {
 					message
+					exception
+					location
 					}
 				}
 				setProjectAutoValidationStampProperty(input: {
@@ -125,6 +121,8 @@ can directly give the name of the Git branch.
 				}) {
 					errors {
 						message
+						exception
+						location
 					}
 				}
 				setProjectAutoPromotionLevelProperty(input: {
@@ -133,6 +131,8 @@ can directly give the name of the Git branch.
 				}) {
 					errors {
 						message
+						exception
+						location
 					}
 				}
 			}
@@ -31,9 +31,7 @@ For example:
 
 		var data struct {
 			CheckAutoVersioning struct {
-				Errors []struct {
-					Message string
-				}
+				Errors []client.GraphQLError
 			}
 		}
 
@@ -51,6 +49,8 @@ For example:
 				}) {
 					errors {
 					  message
+					  exception
+					  location
 					}
 				}
 			}
@@ -0,0 +1,406 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"yontrack/client"
+	"yontrack/config"
+	"yontrack/output"
+
+	"github.com/spf13/cobra"
+)
+
+var buildCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Checks that a build satisfies a set of conditions",
+	Long: `Checks that a build satisfies a set of conditions, failing with the exit code 9
+and listing all the unmet conditions otherwise.
+
+  --promotion NAME                          the build has this promotion
+  --validation NAME[:STATUS]                the last run of this validation has the given status (PASSED by default)
+  --all-validations-passed                  all the validations of the branch have run for the build, and their last runs are PASSED
+  --dependency PROJECT[@QUALIFIER][:PROMO]  the build is linked to a build of PROJECT (with an optional qualifier)
+                                            having the PROMO promotion
+
+All the flags but --all-validations-passed can be repeated.
+
+	yontrack build check --project my-project --branch main --build 42 \
+		--promotion BRONZE --promotion SILVER \
+		--all-validations-passed \
+		--dependency my-library:GOLD
+
+--project defaults to YONTRACK_PROJECT_NAME, --branch to YONTRACK_BRANCH_NAME and --build to
+YONTRACK_BUILD_NAME. The build can also be identified using --id or --version.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		printer, err := output.FromCommand(cmd)
+		if err != nil {
+			return err
+		}
+		selector, err := buildSelectorFromCommand(cmd)
+		if err != nil {
+			return err
+		}
+		conditions, err := buildCheckConditionsFromCommand(cmd)
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.GetSelectedConfiguration()
+		if err != nil {
+			return err
+		}
+		state, err := loadBuildCheckState(cfg, selector)
+		if err != nil {
+			return err
+		}
+
+		results := conditions.check(state)
+		var unmet []string
+		rows := make([][]string, 0, len(results))
+		for _, result := range results {
+			status := "OK"
+			if !result.Satisfied {
+				status = "KO"
+				unmet = append(unmet, fmt.Sprintf("%s (%s)", result.Condition, result.Detail))
+			}
+			rows = append(rows, []string{status, result.Condition, result.Detail})
+		}
+		if err := printer.Print(&output.Result{
+			Value:   results,
+			Headers: []string{"STATUS", "CONDITION", "DETAIL"},
+			Rows:    rows,
+			Plain: func(w io.Writer) error {
+				for _, row := range rows {
+					_, _ = fmt.Fprintf(w, "[%s] %s: %s\n", row[0], row[1], row[2])
+				}
+				return nil
+			},
+		}); err != nil {
+			return err
+		}
+
+		if len(unmet) > 0 {
+			return &gateError{
+				Message: fmt.Sprintf("%s does not satisfy %d condition(s):\n- %s", selector, len(unmet), strings.Join(unmet, "\n- ")),
+			}
+		}
+		return nil
+	},
+}
+
+// buildCheckConditions is the set of conditions a build must satisfy
+type buildCheckConditions struct {
+	Promotions           []string
+	Validations          []buildCheckValidation
+	AllValidationsPassed bool
+	Dependencies         []buildCheckDependency
+}
+
+type buildCheckValidation struct {
+	Stamp  string
+	Status string
+}
+
+type buildCheckDependency struct {
+	Project   string
+	Qualifier string
+	Promotion string
+}
+
+// buildCheckState is the state of the build, as far as the conditions are concerned
+type buildCheckState struct {
+	Promotions []string
+	// Last status of each validation stamp, in the order returned by Ontrack
+	Validations []buildValidationView
+	// All the validation stamps of the branch, including the ones without any run
+	Stamps []string
+	// Builds the build is linked to
+	Dependencies []buildCheckDependencyState
+}
+
+type buildCheckDependencyState struct {
+	Project    string
+	Qualifier  string
+	Build      string
+	Promotions []string
+}
+
+// buildCheckResult is the result of the check of one condition
+type buildCheckResult struct {
+	Condition string `json:"condition"`
+	Satisfied bool   `json:"satisfied"`
+	Detail    string `json:"detail"`
+}
+
+func buildCheckConditionsFromCommand(cmd *cobra.Command) (buildCheckConditions, error) {
+	var conditions buildCheckConditions
+	var err error
+	if conditions.Promotions, err = cmd.Flags().GetStringSlice("promotion"); err != nil {
+		return conditions, err
+	}
+	if conditions.AllValidationsPassed, err = cmd.Flags().GetBool("all-validations-passed"); err != nil {
+		return conditions, err
+	}
+	validations, err := cmd.Flags().GetStringSlice("validation")
+	if err != nil {
+		return conditions, err
+	}
+	for _, value := range validations {
+		stamp, status := parseValidationCriteria(value)
+		if status == "" {
+			status = "PASSED"
+		}
+		conditions.Validations = append(conditions.Validations, buildCheckValidation{Stamp: stamp, Status: status})
+	}
+	dependencies, err := cmd.Flags().GetStringSlice("dependency")
+	if err != nil {
+		return conditions, err
+	}
+	for _, value := range dependencies {
+		conditions.Dependencies = append(conditions.Dependencies, parseBuildCheckDependency(value))
+	}
+	if len(conditions.Promotions) == 0 && len(conditions.Validations) == 0 && !conditions.AllValidationsPassed && len(conditions.Dependencies) == 0 {
+		return conditions, fmt.Errorf("at least one condition is required (--promotion, --validation, --all-validations-passed or --dependency)")
+	}
+	return conditions, nil
+}
+
+// parseBuildCheckDependency parses a PROJECT[@QUALIFIER][:PROMOTION] dependency condition
+func parseBuildCheckDependency(value string) buildCheckDependency {
+	var dependency buildCheckDependency
+	parts := SplitOnce(value, ':')
+	if len(parts) == 2 {
+		dependency.Promotion = parts[1]
+	}
+	parts = SplitOnce(parts[0], '@')
+	dependency.Project = parts[0]
+	if len(parts) == 2 {
+		dependency.Qualifier = parts[1]
+	}
+	return dependency
+}
+
+func (d buildCheckDependency) String() string {
+	name := d.Project
+	if d.Qualifier != "" {
+		name += "@" + d.Qualifier
+	}
+	if d.Promotion != "" {
+		return fmt.Sprintf("dependency %s with promotion %s", name, d.Promotion)
+	}
+	return fmt.Sprintf("dependency %s", name)
+}
+
+// check checks all the conditions against the state of the build
+func (c buildCheckConditions) check(state buildCheckState) []buildCheckResult {
+	var results []buildCheckResult
+	for _, promotion := range c.Promotions {
+		result := buildCheckResult{Condition: "promotion " + promotion, Satisfied: containsString(state.Promotions, promotion)}
+		if result.Satisfied {
+			result.Detail = "granted"
+		} else {
+			result.Detail = "not granted"
+		}
+		results = append(results, result)
+	}
+	for _, validation := range c.Validations {
+		result := buildCheckResult{Condition: fmt.Sprintf("validation %s is %s", validation.Stamp, validation.Status), Detail: "not run"}
+		for _, run := range state.Validations {
+			if run.Stamp == validation.Stamp {
+				result.Satisfied = run.Status == validation.Status
+				result.Detail = run.Status
+			}
+		}
+		results = append(results, result)
+	}
+	if c.AllValidationsPassed {
+		var failed []string
+		for _, run := range state.Validations {
+			if run.Status != "PASSED" {
+				failed = append(failed, run.Stamp+":"+run.Status)
+			}
+		}
+		// The validations which never ran are not passed
+		for _, stamp := range state.Stamps {
+			run := false
+			for _, validation := range state.Validations {
+				if validation.Stamp == stamp {
+					run = true
+				}
+			}
+			if !run {
+				failed = append(failed, stamp+":not run")
+			}
+		}
+		result := buildCheckResult{Condition: "all validations passed", Satisfied: len(failed) == 0}
+		if result.Satisfied && len(state.Validations) == 0 {
+			result.Detail = "no validation stamp on the branch"
+		} else if result.Satisfied {
+			result.Detail = fmt.Sprintf("%d validation(s) passed", len(state.Validations))
+		} else {
+			result.Detail = strings.Join(failed, ", ")
+		}
+		results = append(results, result)
+	}
+	for _, dependency := range c.Dependencies {
+		result := buildCheckResult{Condition: dependency.String(), Detail: "no link"}
+		var candidates []string
+		for _, link := range state.Dependencies {
+			if link.Project != dependency.Project || (dependency.Qualifier != "" && link.Qualifier != dependency.Qualifier) {
+				continue
+			}
+			if dependency.Promotion == "" || containsString(link.Promotions, dependency.Promotion) {
+				result.Satisfied = true
+				result.Detail = "linked to build " + link.Build
+				break
+			}
+			candidates = append(candidates, link.Build)
+		}
+		if !result.Satisfied && len(candidates) > 0 {
+			result.Detail = fmt.Sprintf("linked to build(s) %s without promotion %s", strings.Join(candidates, ", "), dependency.Promotion)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func loadBuildCheckState(cfg *config.Config, selector buildSelector) (buildCheckState, error) {
+	var state buildCheckState
+	var data struct {
+		Builds []struct {
+			Branch struct {
+				ValidationStamps []struct {
+					Name string
+				}
+			}
+			PromotionRuns []struct {
+				PromotionLevel struct {
+					Name string
+				}
+			}
+			Validations []struct {
+				ValidationStamp struct {
+					Name string
+				}
+				ValidationRuns []struct {
+					LastStatus struct {
+						StatusID struct {
+							Id string
+						}
+					}
+				}
+			}
+			UsingQualified struct {
+				PageItems []struct {
+					Qualifier string
+					Build     struct {
+						Name   string
+						Branch struct {
+							Project struct {
+								Name string
+							}
+						}
+						PromotionRuns []struct {
+							PromotionLevel struct {
+								Name string
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	if err := selector.queryBuild(cfg, `
+		query BuildCheck(`+buildSelectorQueryArgs+`) {
+			builds(`+buildSelectorQueryParams+`) {
+				branch {
+					validationStamps {
+						name
+					}
+				}
+				promotionRuns(lastPerLevel: true) {
+					promotionLevel {
+						name
+					}
+				}
+				validations(size: 100) {
+					validationStamp {
+						name
+					}
+					validationRuns(count: 1) {
+						lastStatus {
+							statusID {
+								id
+							}
+						}
+					}
+				}
+				usingQualified(size: 100) {
+					pageItems {
+						qualifier
+						build {
+							name
+							branch {
+								project {
+									name
+								}
+							}
+							promotionRuns(lastPerLevel: true) {
+								promotionLevel {
+									name
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	`, nil, &data); err != nil {
+		return state, err
+	}
+	if len(data.Builds) == 0 {
+		return state, client.NewNotFoundError("%s not found", selector)
+	}
+	build := data.Builds[0]
+	for _, run := range build.PromotionRuns {
+		state.Promotions = append(state.Promotions, run.PromotionLevel.Name)
+	}
+	for _, stamp := range build.Branch.ValidationStamps {
+		state.Stamps = append(state.Stamps, stamp.Name)
+	}
+	for _, validation := range build.Validations {
+		if len(validation.ValidationRuns) > 0 {
+			state.Validations = append(state.Validations, buildValidationView{
+				Stamp:  validation.ValidationStamp.Name,
+				Status: validation.ValidationRuns[0].LastStatus.StatusID.Id,
+			})
+		}
+	}
+	for _, item := range build.UsingQualified.PageItems {
+		dependency := buildCheckDependencyState{
+			Project:   item.Build.Branch.Project.Name,
+			Qualifier: item.Qualifier,
+			Build:     item.Build.Name,
+		}
+		for _, run := range item.Build.PromotionRuns {
+			dependency.Promotions = append(dependency.Promotions, run.PromotionLevel.Name)
+		}
+		state.Dependencies = append(state.Dependencies, dependency)
+	}
+	return state, nil
+}
+
+func init() {
+	buildCmd.AddCommand(buildCheckCmd)
+	registerBuildCheckFlags(buildCheckCmd)
+}
+
+func registerBuildCheckFlags(cmd *cobra.Command) {
+	registerBuildSelectorFlags(cmd)
+	cmd.Flags().StringSlice("promotion", nil, "Promotion the build must have")
+	cmd.Flags().StringSlice("validation", nil, "Validation NAME[:STATUS] the build must have (PASSED by default)")
+	cmd.Flags().Bool("all-validations-passed", false, "All the validations of the branch must have run for the build and be PASSED")
+	cmd.Flags().StringSlice("dependency", nil, "PROJECT[@QUALIFIER][:PROMOTION] build the build must be linked to")
+}
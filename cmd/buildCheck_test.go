@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBuildCheckDependency(t *testing.T) {
+	assert.Equal(t, buildCheckDependency{Project: "lib"}, parseBuildCheckDependency("lib"))
+	assert.Equal(t, buildCheckDependency{Project: "lib", Promotion: "GOLD"}, parseBuildCheckDependency("lib:GOLD"))
+	assert.Equal(t, buildCheckDependency{Project: "lib", Qualifier: "tests", Promotion: "GOLD"}, parseBuildCheckDependency("lib@tests:GOLD"))
+}
+
+func TestBuildCheckConditions_Check(t *testing.T) {
+	conditions := buildCheckConditions{
+		Promotions: []string{"BRONZE", "SILVER"},
+		Validations: []buildCheckValidation{
+			{Stamp: "build", Status: "PASSED"},
+			{Stamp: "deploy", Status: "PASSED"},
+		},
+		AllValidationsPassed: true,
+		Dependencies: []buildCheckDependency{
+			{Project: "lib", Promotion: "GOLD"},
+			{Project: "other", Promotion: "GOLD"},
+			{Project: "tool", Qualifier: "tests"},
+		},
+	}
+	state := buildCheckState{
+		Promotions:  []string{"BRONZE"},
+		Validations: []buildValidationView{{Stamp: "build", Status: "PASSED"}, {Stamp: "sonar", Status: "WARNING"}},
+		Stamps:      []string{"build", "sonar", "deploy"},
+		Dependencies: []buildCheckDependencyState{
+			{Project: "lib", Build: "1", Promotions: []string{"SILVER"}},
+			{Project: "lib", Build: "2", Promotions: []string{"SILVER", "GOLD"}},
+			{Project: "other", Build: "5"},
+			{Project: "tool", Qualifier: "tests", Build: "9"},
+		},
+	}
+	assert.Equal(t, []buildCheckResult{
+		{Condition: "promotion BRONZE", Satisfied: true, Detail: "granted"},
+		{Condition: "promotion SILVER", Satisfied: false, Detail: "not granted"},
+		{Condition: "validation build is PASSED", Satisfied: true, Detail: "PASSED"},
+		{Condition: "validation deploy is PASSED", Satisfied: false, Detail: "not run"},
+		{Condition: "all validations passed", Satisfied: false, Detail: "sonar:WARNING, deploy:not run"},
+		{Condition: "dependency lib with promotion GOLD", Satisfied: true, Detail: "linked to build 2"},
+		{Condition: "dependency other with promotion GOLD", Satisfied: false, Detail: "linked to build(s) 5 without promotion GOLD"},
+		{Condition: "dependency tool@tests", Satisfied: true, Detail: "linked to build 9"},
+	}, conditions.check(state))
+}
+
+func TestBuildCheckConditions_AllValidationsPassed(t *testing.T) {
+	conditions := buildCheckConditions{AllValidationsPassed: true}
+
+	// Validations which never ran are not passed
+	assert.Equal(t, []buildCheckResult{
+		{Condition: "all validations passed", Satisfied: false, Detail: "build:not run, deploy:not run"},
+	}, conditions.check(buildCheckState{Stamps: []string{"build", "deploy"}}))
+
+	assert.Equal(t, []buildCheckResult{
+		{Condition: "all validations passed", Satisfied: true, Detail: "2 validation(s) passed"},
+	}, conditions.check(buildCheckState{
+		Stamps:      []string{"build", "deploy"},
+		Validations: []buildValidationView{{Stamp: "build", Status: "PASSED"}, {Stamp: "deploy", Status: "PASSED"}},
+	}))
+}
+
+func TestBuildCheckConditionsFromCommand(t *testing.T) {
+	parse := func(args ...string) (buildCheckConditions, error) {
+		return buildCheckConditionsFromCommand(newTestCommand(t, registerBuildCheckFlags, args...))
+	}
+
+	conditions, err := parse("--promotion", "BRONZE,SILVER", "--validation", "build", "--validation", "sonar:warning", "--dependency", "lib:GOLD")
+	require.NoError(t, err)
+	assert.Equal(t, buildCheckConditions{
+		Promotions:   []string{"BRONZE", "SILVER"},
+		Validations:  []buildCheckValidation{{Stamp: "build", Status: "PASSED"}, {Stamp: "sonar", Status: "WARNING"}},
+		Dependencies: []buildCheckDependency{{Project: "lib", Promotion: "GOLD"}},
+	}, conditions)
+
+	_, err = parse()
+	assert.Error(t, err)
+}
@@ -133,9 +133,7 @@ func buildLink(cmd *cobra.Command) error {
 	// Call linkBuildById
 	var data struct {
 		LinkBuildById struct {
-			Errors []struct {
-				Message string
-			}
+			Errors []client.GraphQLError
 		}
 	}
 	if err := client.GraphQLCall(cfg, `
@@ -151,6 +149,8 @@ func buildLink(cmd *cobra.Command) error {
 			}) {
 				errors {
 					message
+					exception
+					location
 				}
 			}
 		}
@@ -204,9 +204,9 @@ func resolveBuildID(cfg *config.Config, project, name, version string) (int, err
 
 	if len(data.Builds) == 0 {
 		if name != "" {
-			return 0, fmt.Errorf("no build found with name %q in project %q", name, project)
+			return 0, client.NewNotFoundError("no build found with name %q in project %q", name, project)
 		}
-		return 0, fmt.Errorf("no build found with version %q in project %q", version, project)
+		return 0, client.NewNotFoundError("no build found with version %q in project %q", version, project)
 	}
 	id, err := strconv.Atoi(data.Builds[0].Id)
 	if err != nil {
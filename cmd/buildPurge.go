@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"yontrack/client"
+	"yontrack/output"
+	"yontrack/utils"
+
+	"github.com/spf13/cobra"
+)
+
+// Actions of the build purge
+const (
+	purgeKeep   = "keep"
+	purgeDelete = "delete"
+)
+
+var buildPurgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Deletes the old builds of a branch",
+	Long: `Deletes the old builds of a branch.
+
+The N most recent builds are kept (--keep, 10 by default), together with
+the builds having any of the --keep-promotion promotions and, when using
+--keep-release, the builds having a release label. All the other builds
+are deleted.
+
+The plan is always displayed first and a confirmation is asked, unless --yes is used.
+Use --dry-run to only display the plan.
+
+	yontrack build purge --project my-project --branch main --keep 20 --dry-run
+	yontrack build purge --project my-project --branch main --keep 5 --keep-promotion GOLD --keep-release --yes
+
+--project defaults to YONTRACK_PROJECT_NAME and --branch defaults to YONTRACK_BRANCH_NAME.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		printer, err := output.FromCommand(cmd)
+		if err != nil {
+			return err
+		}
+		criteria, err := buildPurgeCriteriaFromCommand(cmd)
+		if err != nil {
+			return err
+		}
+		limit, err := cmd.Flags().GetInt("limit")
+		if err != nil {
+			return err
+		}
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return err
+		}
+		yes, err := cmd.Flags().GetBool("yes")
+		if err != nil {
+			return err
+		}
+
+		cfg, branch, _, err := loadBranch(cmd, limit)
+		if err != nil {
+			return err
+		}
+
+		plan, err := planBuildPurge(branch.Builds, criteria)
+		if err != nil {
+			return err
+		}
+		deleted := 0
+		rows := make([][]string, 0, len(plan))
+		for _, item := range plan {
+			rows = append(rows, []string{item.Build, item.Creation, item.Action, item.Reason})
+			if item.Action == purgeDelete {
+				deleted++
+			}
+		}
+		if err := printer.Print(&output.Result{
+			Value:   plan,
+			Headers: []string{"BUILD", "CREATION", "ACTION", "REASON"},
+			Rows:    rows,
+			Plain: func(w io.Writer) error {
+				for _, item := range plan {
+					if item.Action == purgeDelete {
+						_, _ = fmt.Fprintf(w, "delete %s (created: %s)\n", item.Build, item.Creation)
+					}
+				}
+				return nil
+			},
+		}); err != nil {
+			return err
+		}
+
+		name := branch.Project.Name + "/" + branch.Name
+		if dryRun || deleted == 0 {
+			_, _ = fmt.Fprintf(os.Stderr, "%d build(s) to delete and %d build(s) to keep in branch %s\n", deleted, len(plan)-deleted, name)
+			return nil
+		}
+		if !yes {
+			ok, err := utils.Confirm(os.Stdin, os.Stderr, fmt.Sprintf("Deleting %d build(s) of branch %s. Continue?", deleted, name))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return fmt.Errorf("purge of branch %s cancelled", name)
+			}
+		}
+
+		for _, item := range plan {
+			if item.Action == purgeDelete {
+				if err := client.DeleteBuild(cfg, item.id); err != nil {
+					return fmt.Errorf("cannot delete build %s: %w", item.Build, err)
+				}
+			}
+		}
+		_, _ = fmt.Fprintf(os.Stderr, "%d build(s) deleted and %d build(s) kept in branch %s\n", deleted, len(plan)-deleted, name)
+		return nil
+	},
+}
+
+// buildPurgeCriteria defines which builds must be kept
+type buildPurgeCriteria struct {
+	Keep           int
+	KeepPromotions []string
+	KeepRelease    bool
+}
+
+// buildPurgeItem is the decision taken for one build
+type buildPurgeItem struct {
+	id       int
+	Build    string `json:"build"`
+	Creation string `json:"creation"`
+	Action   string `json:"action"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+func buildPurgeCriteriaFromCommand(cmd *cobra.Command) (buildPurgeCriteria, error) {
+	var criteria buildPurgeCriteria
+	var err error
+	if criteria.Keep, err = cmd.Flags().GetInt("keep"); err != nil {
+		return criteria, err
+	}
+	if criteria.KeepPromotions, err = cmd.Flags().GetStringSlice("keep-promotion"); err != nil {
+		return criteria, err
+	}
+	if criteria.KeepRelease, err = cmd.Flags().GetBool("keep-release"); err != nil {
+		return criteria, err
+	}
+	if criteria.Keep < 0 {
+		return criteria, fmt.Errorf("--keep must be positive")
+	}
+	return criteria, nil
+}
+
+// planBuildPurge decides which builds must be kept or deleted. The builds are expected
+// to be sorted from the most recent one.
+func planBuildPurge(builds []client.BranchBuild, criteria buildPurgeCriteria) ([]buildPurgeItem, error) {
+	plan := make([]buildPurgeItem, 0, len(builds))
+	for index, build := range builds {
+		id, err := build.IntID()
+		if err != nil {
+			return nil, err
+		}
+		action, reason := purgeKeep, buildPurgeKeepReason(index, build, criteria)
+		if reason == "" {
+			action = purgeDelete
+		}
+		plan = append(plan, buildPurgeItem{
+			id:       id,
+			Build:    build.Name,
+			Creation: build.Creation.Time,
+			Action:   action,
+			Reason:   reason,
+		})
+	}
+	return plan, nil
+}
+
+func buildPurgeKeepReason(index int, build client.BranchBuild, criteria buildPurgeCriteria) string {
+	if index < criteria.Keep {
+		return "recent"
+	}
+	var promotions []string
+	for _, promotion := range build.Promotions() {
+		for _, keep := range criteria.KeepPromotions {
+			if promotion == keep {
+				promotions = append(promotions, promotion)
+			}
+		}
+	}
+	if len(promotions) > 0 {
+		return "promotion " + strings.Join(promotions, ",")
+	}
+	if criteria.KeepRelease && build.Release() != "" {
+		return "release " + build.Release()
+	}
+	return ""
+}
+
+func init() {
+	buildCmd.AddCommand(buildPurgeCmd)
+	registerBuildPurgeFlags(buildPurgeCmd)
+}
+
+func registerBuildPurgeFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("project", "p", "", "Project name")
+	cmd.Flags().StringP("branch", "b", "", "Branch name or Git branch name")
+	cmd.Flags().Int("keep", 10, "Number of most recent builds to keep")
+	cmd.Flags().StringSlice("keep-promotion", nil, "Keeps the builds having this promotion")
+	cmd.Flags().Bool("keep-release", false, "Keeps the builds having a release label")
+	cmd.Flags().Int("limit", 1000, "Maximum number of builds to consider, from the most recent one")
+	cmd.Flags().Bool("dry-run", false, "Only displays the builds which would be deleted")
+	cmd.Flags().BoolP("yes", "y", false, "Does not ask for confirmation")
+}
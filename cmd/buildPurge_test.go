@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+	"yontrack/client"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanBuildPurge(t *testing.T) {
+	var builds []client.BranchBuild
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"id": "5", "name": "5", "creation": {"time": "t5"}},
+		{"id": "4", "name": "4", "creation": {"time": "t4"}},
+		{"id": "3", "name": "3", "creation": {"time": "t3"}, "promotionRuns": [{"promotionLevel": {"name": "SILVER"}}, {"promotionLevel": {"name": "GOLD"}}]},
+		{"id": "2", "name": "2", "creation": {"time": "t2"}, "releaseProperty": {"value": {"name": "1.0.0"}}},
+		{"id": "1", "name": "1", "creation": {"time": "t1"}}
+	]`), &builds))
+
+	plan, err := planBuildPurge(builds, buildPurgeCriteria{
+		Keep:           2,
+		KeepPromotions: []string{"GOLD"},
+		KeepRelease:    true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []buildPurgeItem{
+		{id: 5, Build: "5", Creation: "t5", Action: purgeKeep, Reason: "recent"},
+		{id: 4, Build: "4", Creation: "t4", Action: purgeKeep, Reason: "recent"},
+		{id: 3, Build: "3", Creation: "t3", Action: purgeKeep, Reason: "promotion GOLD"},
+		{id: 2, Build: "2", Creation: "t2", Action: purgeKeep, Reason: "release 1.0.0"},
+		{id: 1, Build: "1", Creation: "t1", Action: purgeDelete},
+	}, plan)
+
+	plan, err = planBuildPurge(builds, buildPurgeCriteria{Keep: 1})
+	require.NoError(t, err)
+	var deleted []string
+	for _, item := range plan {
+		if item.Action == purgeDelete {
+			deleted = append(deleted, item.Build)
+		}
+	}
+	assert.Equal(t, []string{"4", "3", "2", "1"}, deleted)
+}
@@ -1,11 +1,12 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
+	"io"
+	"strings"
 	"yontrack/client"
 	config "yontrack/config"
+	"yontrack/output"
 	"yontrack/utils"
 
 	"github.com/spf13/cobra"
@@ -30,8 +31,26 @@ to look for a build using its commit:
 
     yontrack build search --project PROJECT --branch BRANCH --commit commit
 
+or for the builds of a branch created since the last BRONZE one, with a PASSED build validation:
+
+    yontrack build search --project PROJECT --branch BRANCH --since-promotion BRONZE --with-validation build:PASSED
+
+or for the builds having a given release label and using a given dependency:
+
+    yontrack build search --project PROJECT --property release=1.2.3 --linked-to "dependency:*"
+
 By default, only the build names are printed, one per line.
 
+The --output flag prints the builds with their release label, creation date, promotions
+and the status of their validations:
+
+    yontrack build search --project PROJECT --output table
+    yontrack build search --project PROJECT --output json
+    yontrack build search --project PROJECT --output ndjson | jq .Name
+
+When --count is 1, the json, yaml and template outputs render the build itself instead of a list
+and the env output becomes available.
+
 You can change the display options using additional flags - see 'yontrack build search --help' to get their list.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 
@@ -39,86 +58,181 @@ You can change the display options using additional flags - see 'yontrack build
 		if err != nil {
 			return err
 		}
+		printer, err := output.FromCommand(cmd)
+		if err != nil {
+			return err
+		}
 
 		// Project vs. branch search
 		if branch == "" {
-			return projectSearch(cmd, project)
+			return projectSearch(cmd, printer, project)
 		} else {
-			return branchSearch(cmd, project, branch)
+			return branchSearch(cmd, printer, project, branch)
 		}
 	},
 }
 
+const gitCommitPropertyType = "net.nemerosa.ontrack.extension.git.property.GitCommitPropertyType"
+
+// buildPropertyAliases maps short names to the FQCN of the build property types
+var buildPropertyAliases = map[string]string{
+	"release":    releasePropertyType,
+	"git-commit": gitCommitPropertyType,
+}
+
+// buildSearchFragment lists the build fields returned by the searches. The promotions and the
+// validations are only needed by the --output formats and are fetched when $details is true.
+const buildSearchFragment = `
+	fragment BuildSearchFields on Build {
+		id
+		name
+		displayName
+		creation {
+			time
+		}
+		releaseProperty {
+			value
+		}
+		promotionRuns(lastPerLevel: true) @include(if: $details) {
+			promotionLevel {
+				name
+			}
+		}
+		validations(size: 100) @include(if: $details) {
+			validationStamp {
+				name
+			}
+			validationRuns(count: 1) {
+				lastStatus {
+					statusID {
+						id
+					}
+				}
+			}
+		}
+		branch {
+			id
+			name
+			displayName
+			project {
+				id
+				name
+			}
+		}
+	}
+`
+
 type build struct {
 	Id          string
 	Name        string
 	DisplayName string
-	Branch      struct {
-		Id          string
-		Name        string
-		DisplayName string
-		Project     struct {
-			Id   string
+	Branch      buildBranch
+	Creation    struct {
+		Time string
+	}
+	ReleaseProperty *struct {
+		Value struct {
 			Name string
 		}
 	}
+	PromotionRuns []struct {
+		PromotionLevel struct {
+			Name string
+		}
+	}
+	Validations []struct {
+		ValidationStamp struct {
+			Name string
+		}
+		ValidationRuns []struct {
+			LastStatus struct {
+				StatusID struct {
+					Id string
+				}
+			}
+		}
+	}
+}
+
+type buildBranch struct {
+	Id          string
+	Name        string
+	DisplayName string
+	Project     struct {
+		Id   string
+		Name string
+	}
+}
+
+// buildView is the flattened representation of a build used by the outputs
+type buildView struct {
+	Id          string
+	Name        string
+	DisplayName string
+	Branch      buildBranch
+	Release     string `json:",omitempty"`
+	Creation    string `json:",omitempty"`
+	Promotions  []string
+	Validations []buildValidationView
+}
+
+type buildValidationView struct {
+	Stamp  string
+	Status string
+}
+
+func (b build) view() buildView {
+	view := buildView{
+		Id:          b.Id,
+		Name:        b.Name,
+		DisplayName: b.DisplayName,
+		Branch:      b.Branch,
+		Creation:    b.Creation.Time,
+		Promotions:  []string{},
+		Validations: []buildValidationView{},
+	}
+	if b.ReleaseProperty != nil {
+		view.Release = b.ReleaseProperty.Value.Name
+	}
+	for _, run := range b.PromotionRuns {
+		view.Promotions = append(view.Promotions, run.PromotionLevel.Name)
+	}
+	for _, validation := range b.Validations {
+		if len(validation.ValidationRuns) > 0 {
+			view.Validations = append(view.Validations, buildValidationView{
+				Stamp:  validation.ValidationStamp.Name,
+				Status: validation.ValidationRuns[0].LastStatus.StatusID.Id,
+			})
+		}
+	}
+	return view
 }
 
 type buildList struct {
 	Builds []build
 }
 
-func projectSearch(cmd *cobra.Command, project string) error {
+func projectSearch(cmd *cobra.Command, printer *output.Printer, project string) error {
 	// Query
 	query := `
 		query BuildProjectSearch(
 			$project: String!,
-			$buildProjectFilter: BuildSearchForm!
+			$buildProjectFilter: BuildSearchForm!,
+			$details: Boolean!
 		) {
 			builds(
 				project: $project,
 				buildProjectFilter: $buildProjectFilter
 			) {
-				id
-				name
-				displayName
-				branch {
-					id
-					name
-					displayName
-					project {
-						id
-						name
-					}
-				}
+				...BuildSearchFields
 			}
 		}
-	`
+	` + buildSearchFragment
 
 	// Search form
-	form := make(map[string]interface{})
-	if err := fillFormWithProperty(cmd, &form, "property", "propertyValue"); err != nil {
-		return err
-	}
-	if err := fillFormWithCount(cmd, &form, "maximumCount"); err != nil {
-		return err
-	}
-
-	if err := fillFormWithWithPromotion(cmd, &form, "promotionName"); err != nil {
-		return err
-	}
-
-	name, err := cmd.Flags().GetString("name")
+	form, err := projectSearchForm(cmd)
 	if err != nil {
 		return err
-	} else if name != "" {
-		form["buildName"] = name
-		nameExact, err := cmd.Flags().GetBool("name-exact")
-		if err != nil {
-			return err
-		} else if nameExact {
-			form["buildExactMatch"] = true
-		}
 	}
 
 	// Gets the configuration
@@ -134,53 +248,37 @@ func projectSearch(cmd *cobra.Command, project string) error {
 	if err := client.GraphQLCall(cfg, query, map[string]interface{}{
 		"project":            project,
 		"buildProjectFilter": form,
+		"details":            printer.IsSet(),
 	}, &data); err != nil {
 		return err
 	}
 
 	// Displaying the data
-	return displayBuilds(cmd, &data)
+	return displayBuilds(cmd, printer, &data)
 }
 
-func branchSearch(cmd *cobra.Command, project string, branch string) error {
+func branchSearch(cmd *cobra.Command, printer *output.Printer, project string, branch string) error {
 	// Query
 	query := `
 		query BuildBranchSearch(
 			$project: String!,
 			$branch: String!,
-			$buildBranchFilter: StandardBuildFilter!
+			$buildBranchFilter: StandardBuildFilter!,
+			$details: Boolean!
 		) {
 			builds(
 				project: $project,
 				branch: $branch,
 				buildBranchFilter: $buildBranchFilter
 			) {
-				id
-				name
-				displayName
-				branch {
-					id
-					name
-					displayName
-					project {
-						id
-						name
-					}
-				}
+				...BuildSearchFields
 			}
 		}
-	`
+	` + buildSearchFragment
 
 	// Search form
-	form := make(map[string]interface{})
-	if err := fillFormWithProperty(cmd, &form, "withProperty", "withPropertyValue"); err != nil {
-		return err
-	}
-	if err := fillFormWithCount(cmd, &form, "count"); err != nil {
-		return err
-	}
-
-	if err := fillFormWithWithPromotion(cmd, &form, "withPromotionLevel"); err != nil {
+	form, err := branchSearchForm(cmd)
+	if err != nil {
 		return err
 	}
 
@@ -198,15 +296,16 @@ func branchSearch(cmd *cobra.Command, project string, branch string) error {
 		"project":           project,
 		"branch":            branch,
 		"buildBranchFilter": form,
+		"details":           printer.IsSet(),
 	}, &data); err != nil {
 		return err
 	}
 
 	// Displaying the data
-	return displayBuilds(cmd, &data)
+	return displayBuilds(cmd, printer, &data)
 }
 
-func displayBuilds(cmd *cobra.Command, data *buildList) error {
+func displayBuilds(cmd *cobra.Command, printer *output.Printer, data *buildList) error {
 	displayBranch, err := cmd.Flags().GetBool("display-branch")
 	if err != nil {
 		return err
@@ -215,74 +314,200 @@ func displayBuilds(cmd *cobra.Command, data *buildList) error {
 	if err != nil {
 		return err
 	}
-	output, err := cmd.Flags().GetString("output")
+	acceptNotFound, err := cmd.Flags().GetBool("accept-not-found")
 	if err != nil {
 		return err
 	}
-	acceptNotFound, err := cmd.Flags().GetBool("accept-not-found")
+	count, err := cmd.Flags().GetInt("count")
 	if err != nil {
 		return err
 	}
 
-	if output == "" {
-		for _, build := range data.Builds {
-			if displayBranch {
-				fmt.Printf(build.Branch.Name)
-			} else if displayId {
-				fmt.Println(build.Id)
-			} else {
-				fmt.Println(build.Name)
-			}
+	// Looking for one build only
+	single := count == 1
+	if single && (printer.IsSet() || printer.Sink != nil) && len(data.Builds) == 0 {
+		if !acceptNotFound {
+			return client.NewNotFoundError("no build found")
 		}
-	} else {
-		count, err := cmd.Flags().GetInt("count")
-		if err != nil {
-			return err
-		}
-		if count == 1 {
-			if len(data.Builds) == 0 {
-				if !acceptNotFound {
-					return fmt.Errorf("no build found")
+		return nil
+	}
+
+	views := make([]buildView, 0, len(data.Builds))
+	rows := make([][]string, 0, len(data.Builds))
+	for _, build := range data.Builds {
+		view := build.view()
+		views = append(views, view)
+		rows = append(rows, buildViewRow(view))
+	}
+
+	result := &output.Result{
+		Value:   views,
+		Headers: []string{"PROJECT", "BRANCH", "ID", "BUILD", "RELEASE", "CREATION", "PROMOTIONS", "VALIDATIONS"},
+		Rows:    rows,
+		Plain: func(w io.Writer) error {
+			for _, build := range data.Builds {
+				if displayBranch {
+					_, _ = fmt.Fprintln(w, build.Branch.Name)
+				} else if displayId {
+					_, _ = fmt.Fprintln(w, build.Id)
+				} else {
+					_, _ = fmt.Fprintln(w, build.Name)
 				}
-			} else {
-				build := data.Builds[0]
-				return displayBuildOutput(build, output)
 			}
-		} else {
-			return fmt.Errorf("output not supported with multiple builds. Set count to 1 or remove the output flag")
+			return nil
+		},
+	}
+	if single && len(data.Builds) == 1 {
+		build := data.Builds[0]
+		result.Value = views[0]
+		result.Env = buildEnvVariables(
+			build.Branch.Project.Id, build.Branch.Project.Name,
+			build.Branch.Id, build.Branch.Name,
+			build.Id, build.Name,
+		)
+	} else if printer.Format == output.Env {
+		return fmt.Errorf("env output only supported for one build. Set count to 1 or use another output format")
+	} else if printer.Sink != nil {
+		return fmt.Errorf("output file only supported for one build. Set count to 1")
+	}
+
+	return printer.Print(result)
+}
+
+func buildViewRow(view buildView) []string {
+	validations := make([]string, 0, len(view.Validations))
+	for _, validation := range view.Validations {
+		validations = append(validations, validation.Stamp+":"+validation.Status)
+	}
+	return []string{
+		view.Branch.Project.Name,
+		view.Branch.Name,
+		view.Id,
+		view.Name,
+		view.Release,
+		view.Creation,
+		strings.Join(view.Promotions, ","),
+		strings.Join(validations, ","),
+	}
+}
+
+// projectSearchForm creates the BuildSearchForm used to search for builds in a project
+func projectSearchForm(cmd *cobra.Command) (map[string]interface{}, error) {
+	if err := checkBranchOnlyFlags(cmd); err != nil {
+		return nil, err
+	}
+
+	form := make(map[string]interface{})
+	if err := fillFormWithProperty(cmd, &form, "property", "propertyValue"); err != nil {
+		return nil, err
+	}
+	if err := fillFormWithCount(cmd, &form, "maximumCount"); err != nil {
+		return nil, err
+	}
+
+	if err := fillFormWithWithPromotion(cmd, &form, "promotionName"); err != nil {
+		return nil, err
+	}
+
+	name, err := cmd.Flags().GetString("name")
+	if err != nil {
+		return nil, err
+	} else if name != "" {
+		form["buildName"] = name
+		nameExact, err := cmd.Flags().GetBool("name-exact")
+		if err != nil {
+			return nil, err
+		} else if nameExact {
+			form["buildExactMatch"] = true
 		}
 	}
 
-	return nil
+	// Only PASSED validations can be looked for at project level
+	withValidation, err := cmd.Flags().GetString("with-validation")
+	if err != nil {
+		return nil, err
+	} else if withValidation != "" {
+		validation, status := parseValidationCriteria(withValidation)
+		if status != "" && status != "PASSED" {
+			return nil, fmt.Errorf("only the PASSED status is supported by --with-validation when searching at project level")
+		}
+		form["validationStampName"] = validation
+	}
+
+	if err := fillForm(cmd, &form, "linked-from", "linkedFrom"); err != nil {
+		return nil, err
+	}
+	if err := fillForm(cmd, &form, "linked-to", "linkedTo"); err != nil {
+		return nil, err
+	}
+
+	return form, nil
 }
 
-func displayBuildOutput(build build, output string) error {
-	if output == "env" {
-		return displayBuildEnv(build)
-	} else if output == "json" {
-		return displayBuildJson(build)
-	} else {
-		return fmt.Errorf("unknown output format: %s", output)
+// branchSearchForm creates the StandardBuildFilter used to search for builds in a branch
+func branchSearchForm(cmd *cobra.Command) (map[string]interface{}, error) {
+	form := make(map[string]interface{})
+	if err := fillFormWithProperty(cmd, &form, "withProperty", "withPropertyValue"); err != nil {
+		return nil, err
 	}
+	if err := fillFormWithCount(cmd, &form, "count"); err != nil {
+		return nil, err
+	}
+
+	if err := fillFormWithWithPromotion(cmd, &form, "withPromotionLevel"); err != nil {
+		return nil, err
+	}
+	if err := fillForm(cmd, &form, "since-promotion", "sincePromotionLevel"); err != nil {
+		return nil, err
+	}
+
+	if err := fillFormWithValidation(cmd, &form, "with-validation", "withValidationStamp", "withValidationStampStatus"); err != nil {
+		return nil, err
+	}
+	if err := fillFormWithValidation(cmd, &form, "since-validation", "sinceValidationStamp", "sinceValidationStampStatus"); err != nil {
+		return nil, err
+	}
+
+	if err := fillForm(cmd, &form, "after-date", "afterDate"); err != nil {
+		return nil, err
+	}
+	if err := fillForm(cmd, &form, "before-date", "beforeDate"); err != nil {
+		return nil, err
+	}
+
+	if err := fillForm(cmd, &form, "linked-from", "linkedFrom"); err != nil {
+		return nil, err
+	}
+	if err := fillForm(cmd, &form, "linked-from-promotion", "linkedFromPromotion"); err != nil {
+		return nil, err
+	}
+	if err := fillForm(cmd, &form, "linked-to", "linkedTo"); err != nil {
+		return nil, err
+	}
+	if err := fillForm(cmd, &form, "linked-to-promotion", "linkedToPromotion"); err != nil {
+		return nil, err
+	}
+
+	return form, nil
 }
 
-func displayBuildEnv(build build) error {
-	_, _ = fmt.Fprintf(os.Stdout, "export YONTRACK_PROJECT_ID=%s\n", build.Branch.Project.Id)
-	_, _ = fmt.Fprintf(os.Stdout, "export YONTRACK_PROJECT_NAME=%s\n", build.Branch.Project.Name)
-	_, _ = fmt.Fprintf(os.Stdout, "export YONTRACK_BRANCH_ID=%s\n", build.Branch.Id)
-	_, _ = fmt.Fprintf(os.Stdout, "export YONTRACK_BRANCH_NAME=%s\n", build.Branch.Name)
-	_, _ = fmt.Fprintf(os.Stdout, "export YONTRACK_BUILD_ID=%s\n", build.Id)
-	_, _ = fmt.Fprintf(os.Stdout, "export YONTRACK_BUILD_NAME=%s\n", build.Name)
-	return nil
+// branchOnlySearchFlags lists the criteria which are available only when searching in a branch
+var branchOnlySearchFlags = []string{
+	"since-promotion",
+	"since-validation",
+	"after-date",
+	"before-date",
+	"linked-from-promotion",
+	"linked-to-promotion",
 }
 
-func displayBuildJson(build build) error {
-	jsonBytes, err := json.MarshalIndent(build, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal build to JSON: %w", err)
+func checkBranchOnlyFlags(cmd *cobra.Command) error {
+	for _, name := range branchOnlySearchFlags {
+		if cmd.Flags().Changed(name) {
+			return fmt.Errorf("--%s can only be used when searching in a branch (use --branch)", name)
+		}
 	}
-	_, err = fmt.Fprintf(os.Stdout, "%s\n", jsonBytes)
-	return err
+	return nil
 }
 
 func fillFormWithCount(cmd *cobra.Command, form *map[string]interface{}, countField string) error {
@@ -303,15 +528,67 @@ func fillFormWithProperty(cmd *cobra.Command, form *map[string]interface{}, prop
 	if err != nil {
 		return err
 	}
+	property, err := cmd.Flags().GetString("property")
+	if err != nil {
+		return err
+	}
 
-	if commit != "" {
-		(*form)[propertyTypeField] = "net.nemerosa.ontrack.extension.git.property.GitCommitPropertyType"
+	if commit != "" && property != "" {
+		return fmt.Errorf("--commit and --property cannot be used together")
+	} else if commit != "" {
+		(*form)[propertyTypeField] = gitCommitPropertyType
 		(*form)[propertyValueField] = commit
+	} else if property != "" {
+		propertyType, value := parsePropertyCriteria(property)
+		(*form)[propertyTypeField] = propertyType
+		if value != "" {
+			(*form)[propertyValueField] = value
+		}
+	}
+
+	return nil
+}
+
+func fillFormWithValidation(cmd *cobra.Command, form *map[string]interface{}, argName string, validationField string, statusField string) error {
+	value, err := cmd.Flags().GetString(argName)
+	if err != nil {
+		return err
+	}
+
+	if value != "" {
+		validation, status := parseValidationCriteria(value)
+		(*form)[validationField] = validation
+		if status != "" {
+			(*form)[statusField] = status
+		}
 	}
 
 	return nil
 }
 
+// parseValidationCriteria parses a NAME[:STATUS] validation criteria
+func parseValidationCriteria(value string) (string, string) {
+	parts := SplitOnce(value, ':')
+	if len(parts) == 2 {
+		return parts[0], strings.ToUpper(parts[1])
+	}
+	return value, ""
+}
+
+// parsePropertyCriteria parses a TYPE[=VALUE] property criteria, where TYPE is either
+// the FQCN of the property type or one of the buildPropertyAliases
+func parsePropertyCriteria(value string) (string, string) {
+	parts := SplitOnce(value, '=')
+	propertyType := parts[0]
+	if alias, ok := buildPropertyAliases[propertyType]; ok {
+		propertyType = alias
+	}
+	if len(parts) == 2 {
+		return propertyType, parts[1]
+	}
+	return propertyType, ""
+}
+
 func fillFormWithWithPromotion(cmd *cobra.Command, form *map[string]interface{}, fieldName string) error {
 	return fillForm(cmd, form, "with-promotion", fieldName)
 }
@@ -331,22 +608,37 @@ func fillForm(cmd *cobra.Command, form *map[string]interface{}, argName string,
 
 func init() {
 	buildCmd.AddCommand(buildSearchCmd)
+	registerBuildSearchFlags(buildSearchCmd)
+	output.RegisterSinkFlags(buildSearchCmd)
+}
 
-	buildSearchCmd.Flags().StringP("project", "p", "", "Name of the project")
-	buildSearchCmd.Flags().StringP("branch", "b", "", "Name of the branch")
+func registerBuildSearchFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("project", "p", "", "Name of the project")
+	cmd.Flags().StringP("branch", "b", "", "Name of the branch")
 
 	// Criteria
-	buildSearchCmd.Flags().Int("count", 10, "Number of builds to return")
-	buildSearchCmd.Flags().String("with-promotion", "", "Builds must have this promotion")
-	buildSearchCmd.Flags().String("name", "", "Builds must have this name or match this regular expression")
-	buildSearchCmd.Flags().Bool("name-exact", true, "If present together with the `name` flag, requires an exact match.")
+	cmd.Flags().Int("count", 10, "Number of builds to return")
+	cmd.Flags().String("with-promotion", "", "Builds must have this promotion")
+	cmd.Flags().String("since-promotion", "", "Builds since the last one having this promotion (branch only)")
+	cmd.Flags().String("with-validation", "", "Builds must have this validation, in the NAME[:STATUS] format (only PASSED is supported at project level)")
+	cmd.Flags().String("since-validation", "", "Builds since the last one having this validation, in the NAME[:STATUS] format (branch only)")
+	cmd.Flags().String("after-date", "", "Builds created on or after this date (branch only)")
+	cmd.Flags().String("before-date", "", "Builds created on or before this date (branch only)")
+	cmd.Flags().String("name", "", "Builds must have this name or match this regular expression")
+	cmd.Flags().Bool("name-exact", true, "If present together with the `name` flag, requires an exact match.")
+
+	// Link criteria
+	cmd.Flags().String("linked-from", "", "Builds must be linked from the builds matching this PROJECT:BUILD expression")
+	cmd.Flags().String("linked-from-promotion", "", "Builds must be linked from a build having this promotion (branch only, requires --linked-from)")
+	cmd.Flags().String("linked-to", "", "Builds must be linked to the builds matching this PROJECT:BUILD expression")
+	cmd.Flags().String("linked-to-promotion", "", "Builds must be linked to a build having this promotion (branch only, requires --linked-to)")
 
 	// Property criteria
-	buildSearchCmd.Flags().String("commit", "", "Commit for the build")
+	cmd.Flags().String("commit", "", "Commit for the build")
+	cmd.Flags().String("property", "", "Builds must have this property, in the TYPE[=VALUE] format. TYPE is either the FQCN of the property or one of: release, git-commit")
 
 	// Display options
-	buildSearchCmd.Flags().StringP("output", "o", "", "How to output the search results (env, json). Incompatible with the `display` options.")
-	buildSearchCmd.Flags().Bool("display-id", false, "Displays the build ID instead of its name.")
-	buildSearchCmd.Flags().Bool("display-branch", false, "Displays the build branch name instead of its name.")
-	buildSearchCmd.Flags().BoolP("accept-not-found", "n", false, "If the search does not return any build, does not fail the command.")
+	cmd.Flags().Bool("display-id", false, "Displays the build ID instead of its name (without --output only).")
+	cmd.Flags().Bool("display-branch", false, "Displays the build branch name instead of its name (without --output only).")
+	cmd.Flags().BoolP("accept-not-found", "n", false, "If the search does not return any build, does not fail the command.")
 }
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildView(t *testing.T) {
+	var data buildList
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"builds": [{
+			"id": "101",
+			"name": "42",
+			"displayName": "1.2.3",
+			"creation": {"time": "2024-01-02T03:04:05Z"},
+			"releaseProperty": {"value": {"name": "1.2.3"}},
+			"promotionRuns": [
+				{"promotionLevel": {"name": "BRONZE"}},
+				{"promotionLevel": {"name": "SILVER"}}
+			],
+			"validations": [
+				{"validationStamp": {"name": "build"}, "validationRuns": [{"lastStatus": {"statusID": {"id": "PASSED"}}}]},
+				{"validationStamp": {"name": "deploy"}, "validationRuns": []}
+			],
+			"branch": {"id": "10", "name": "main", "project": {"id": "1", "name": "yontrack"}}
+		}]
+	}`), &data))
+	require.Len(t, data.Builds, 1)
+
+	view := data.Builds[0].view()
+	assert.Equal(t, "1.2.3", view.Release)
+	assert.Equal(t, "2024-01-02T03:04:05Z", view.Creation)
+	assert.Equal(t, []string{"BRONZE", "SILVER"}, view.Promotions)
+	assert.Equal(t, []buildValidationView{{Stamp: "build", Status: "PASSED"}}, view.Validations)
+
+	assert.Equal(t,
+		[]string{"yontrack", "main", "101", "42", "1.2.3", "2024-01-02T03:04:05Z", "BRONZE,SILVER", "build:PASSED"},
+		buildViewRow(view),
+	)
+}
+
+func TestBuildView_NoRelease(t *testing.T) {
+	view := build{Id: "1", Name: "1"}.view()
+	assert.Equal(t, "", view.Release)
+	assert.Empty(t, view.Promotions)
+	assert.NotNil(t, view.Promotions)
+}
+
+func TestBranchSearchForm(t *testing.T) {
+	cmd := newTestCommand(t, registerBuildSearchFlags,
+		"--count", "5",
+		"--since-promotion", "BRONZE",
+		"--with-validation", "build:passed",
+		"--since-validation", "deploy",
+		"--after-date", "2024-01-01",
+		"--before-date", "2024-02-01",
+		"--property", "release=1.2.3",
+		"--linked-to", "dep:*",
+		"--linked-to-promotion", "GOLD",
+	)
+	form, err := branchSearchForm(cmd)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"count":                     5,
+		"sincePromotionLevel":       "BRONZE",
+		"withValidationStamp":       "build",
+		"withValidationStampStatus": "PASSED",
+		"sinceValidationStamp":      "deploy",
+		"afterDate":                 "2024-01-01",
+		"beforeDate":                "2024-02-01",
+		"withProperty":              releasePropertyType,
+		"withPropertyValue":         "1.2.3",
+		"linkedTo":                  "dep:*",
+		"linkedToPromotion":         "GOLD",
+	}, form)
+}
+
+func TestProjectSearchForm(t *testing.T) {
+	cmd := newTestCommand(t, registerBuildSearchFlags,
+		"--with-validation", "build",
+		"--property", "com.example.MyProperty",
+		"--linked-from", "app:1.*",
+	)
+	form, err := projectSearchForm(cmd)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"maximumCount":        10,
+		"validationStampName": "build",
+		"property":            "com.example.MyProperty",
+		"linkedFrom":          "app:1.*",
+	}, form)
+}
+
+func TestProjectSearchForm_Errors(t *testing.T) {
+	_, err := projectSearchForm(newTestCommand(t, registerBuildSearchFlags, "--after-date", "2024-01-01"))
+	assert.EqualError(t, err, "--after-date can only be used when searching in a branch (use --branch)")
+
+	_, err = projectSearchForm(newTestCommand(t, registerBuildSearchFlags, "--with-validation", "build:FAILED"))
+	assert.Error(t, err)
+
+	_, err = projectSearchForm(newTestCommand(t, registerBuildSearchFlags, "--commit", "abc", "--property", "release=1"))
+	assert.EqualError(t, err, "--commit and --property cannot be used together")
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"yontrack/client"
+	"yontrack/config"
+	"yontrack/utils"
+
+	"github.com/spf13/cobra"
+)
+
+// buildSelector identifies a single build, either by its ID, by its version (release label)
+// in a project, or by its project, branch and name.
+type buildSelector struct {
+	ID      int
+	Project string
+	Branch  string
+	Name    string
+	Version string
+}
+
+func registerBuildSelectorFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("project", "p", "", "Project name")
+	cmd.Flags().StringP("branch", "b", "", "Branch name or Git branch name")
+	cmd.Flags().StringP("build", "n", "", "Build name")
+	cmd.Flags().String("version", "", "Version (release label) of the build, in the project")
+	cmd.Flags().Int("id", 0, "ID of the build")
+}
+
+// buildSelectorFromCommand gets the build identification from the flags registered
+// by registerBuildSelectorFlags
+func buildSelectorFromCommand(cmd *cobra.Command) (buildSelector, error) {
+	var selector buildSelector
+	var err error
+	if selector.ID, err = cmd.Flags().GetInt("id"); err != nil {
+		return selector, err
+	}
+	if selector.Version, err = cmd.Flags().GetString("version"); err != nil {
+		return selector, err
+	}
+	if selector.ID > 0 {
+		if selector.Version != "" {
+			return selector, errors.New("--id and --version cannot be used together")
+		}
+		return selector, nil
+	}
+	if selector.Version != "" {
+		selector.Project, err = utils.GetProjectFlag(cmd)
+		return selector, err
+	}
+	selector.Project, selector.Branch, selector.Name, err = utils.GetProjectBranchBuildFlags(cmd, false, true)
+	return selector, err
+}
+
+// String describes the selected build in error messages
+func (s buildSelector) String() string {
+	if s.ID > 0 {
+		return fmt.Sprintf("build #%d", s.ID)
+	} else if s.Version != "" {
+		return fmt.Sprintf("build with version %s in project %s", s.Version, s.Project)
+	} else {
+		return fmt.Sprintf("build %s/%s/%s", s.Project, s.Branch, s.Name)
+	}
+}
+
+// queryBuild runs a query on the selected build. The query must accept the $id, $project,
+// $branch, $name and $filter variables, and pass them to the builds query. The builds field
+// of data receives the result.
+func (s buildSelector) queryBuild(cfg *config.Config, query string, variables map[string]interface{}, data interface{}) error {
+	vars := map[string]interface{}{
+		"id":      nil,
+		"project": nil,
+		"branch":  nil,
+		"name":    nil,
+		"filter":  nil,
+	}
+	if s.ID > 0 {
+		vars["id"] = s.ID
+	} else if s.Version != "" {
+		vars["project"] = s.Project
+		vars["filter"] = buildSearchFilter("", s.Version)
+	} else {
+		vars["project"] = s.Project
+		vars["branch"] = s.Branch
+		vars["name"] = s.Name
+	}
+	for name, value := range variables {
+		vars[name] = value
+	}
+	return client.GraphQLCall(cfg, query, vars, data)
+}
+
+// buildSelectorQueryArgs are the arguments to declare in a query used by buildSelector.queryBuild
+const buildSelectorQueryArgs = `$id: Int, $project: String, $branch: String, $name: String, $filter: BuildSearchForm`
+
+// buildSelectorQueryParams are the parameters to pass to the builds query used by buildSelector.queryBuild
+const buildSelectorQueryParams = `id: $id, project: $project, branch: $branch, name: $name, buildProjectFilter: $filter`
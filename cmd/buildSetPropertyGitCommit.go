@@ -59,9 +59,7 @@ Example:
 
 		var data struct {
 			SetBuildGitCommitProperty struct {
-				Errors []struct {
-					Message string
-				}
+				Errors []client.GraphQLError
 			}
 		}
 		if err := client.GraphQLCall(cfg, `
@@ -79,6 +77,8 @@ Example:
 				}) {
 					errors {
 						message
+						exception
+						location
 					}
 				}
 			}
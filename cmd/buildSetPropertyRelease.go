@@ -58,9 +58,7 @@ yontrack build set-property --project PROJECT --branch BRANCH --build BUILD rele
 		// Data
 		var data struct {
 			SetBuildReleaseProperty struct {
-				Errors []struct {
-					Message string
-				}
+				Errors []client.GraphQLError
 			}
 		}
 
@@ -80,6 +78,8 @@ yontrack build set-property --project PROJECT --branch BRANCH --build BUILD rele
 				}) {
 					errors {
 						message
+						exception
+						location
 					}
 				}
 			}
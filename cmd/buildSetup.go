@@ -60,19 +60,13 @@ func buildSetup(cmd *cobra.Command) error {
 	// Creates or get the build
 	var data struct {
 		CreateBuildOrGet struct {
-			Errors []struct {
-				Message string
-			}
+			Errors []client.GraphQLError
 		}
 		SetBuildReleaseProperty struct {
-			Errors []struct {
-				Message string
-			}
+			Errors []client.GraphQLError
 		}
 		SetBuildGitCommitProperty struct {
-			Errors []struct {
-				Message string
-			}
+			Errors []client.GraphQLError
 		}
 	}
 	if err := client.GraphQLCall(cfg, `
@@ -96,6 +90,8 @@ func buildSetup(cmd *cobra.Command) error {
 			}) {
 				errors {
 				  message
+				  exception
+				  location
 				}
 			}
 			setBuildReleaseProperty(input: {
@@ -106,6 +102,8 @@ func buildSetup(cmd *cobra.Command) error {
 			}) @include(if: $releaseProperty) {
 				errors {
 					message
+					exception
+					location
 				}
 			}
 			setBuildGitCommitProperty(input: {
@@ -116,6 +114,8 @@ func buildSetup(cmd *cobra.Command) error {
 			}) @include(if: $commitProperty) {
 				errors {
 					message
+					exception
+					location
 				}
 			}
 		}
@@ -0,0 +1,461 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"yontrack/client"
+	"yontrack/config"
+	"yontrack/output"
+
+	"github.com/spf13/cobra"
+)
+
+var buildShowCmd = &cobra.Command{
+	Use:     "show",
+	Aliases: []string{"get"},
+	Short:   "Displays a build",
+	Long: `Displays a build, with its promotions, the last validation run for each validation stamp,
+its links to and from other builds, its properties and its run info.
+
+The build can be identified:
+
+  - by project, branch and name: --project PROJECT --branch BRANCH --build NAME
+  - by project and version:      --project PROJECT --version VERSION
+  - by ID:                       --id ID
+
+--project defaults to YONTRACK_PROJECT_NAME, --branch to YONTRACK_BRANCH_NAME and
+--build to YONTRACK_BUILD_NAME.
+
+Examples:
+
+  yontrack build show --project my-project --branch main --build 42
+  yontrack build show --project my-project --version 1.2.3 --output json
+  yontrack build show --id 1234 --output table
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		printer, err := output.FromCommand(cmd)
+		if err != nil {
+			return err
+		}
+		selector, err := buildSelectorFromCommand(cmd)
+		if err != nil {
+			return err
+		}
+		links, err := cmd.Flags().GetInt("links")
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.GetSelectedConfiguration()
+		if err != nil {
+			return err
+		}
+		build, err := loadBuildDetails(cfg, selector, links)
+		if err != nil {
+			return err
+		}
+		view := build.view()
+
+		rows := buildDetailsRows(view)
+		return printer.Print(&output.Result{
+			Value:   view,
+			Headers: []string{"TYPE", "NAME", "VALUE"},
+			Rows:    rows,
+			Env: buildEnvVariables(
+				view.Branch.Project.Id, view.Branch.Project.Name,
+				view.Branch.Id, view.Branch.Name,
+				view.Id, view.Name,
+			),
+			Plain: func(w io.Writer) error {
+				section := ""
+				for _, row := range rows {
+					if row[0] != section {
+						section = row[0]
+						_, _ = fmt.Fprintf(w, "%s:\n", section)
+					}
+					if row[2] == "" {
+						_, _ = fmt.Fprintf(w, "  %s\n", row[1])
+					} else {
+						_, _ = fmt.Fprintf(w, "  %s: %s\n", row[1], row[2])
+					}
+				}
+				return nil
+			},
+		})
+	},
+}
+
+const buildDetailsFragment = `
+	fragment BuildDetailsFields on Build {
+		id
+		name
+		displayName
+		description
+		creation {
+			time
+			user
+		}
+		branch {
+			id
+			name
+			displayName
+			project {
+				id
+				name
+			}
+		}
+		promotionRuns(lastPerLevel: true) {
+			creation {
+				time
+				user
+			}
+			promotionLevel {
+				name
+			}
+		}
+		validations(size: $validations) {
+			...BuildDetailsValidation
+		}
+		usingQualified(size: $links) {
+			pageItems {
+				...BuildDetailsLink
+			}
+		}
+		usedByQualified(size: $links) {
+			pageItems {
+				...BuildDetailsLink
+			}
+		}
+		properties(hasValue: true) {
+			type {
+				typeName
+				name
+			}
+			value
+		}
+		runInfo {
+			sourceType
+			sourceUri
+			triggerType
+			triggerData
+			runTime
+		}
+	}
+
+	fragment BuildDetailsLink on BuildLink {
+		qualifier
+		build {
+			id
+			name
+			releaseProperty {
+				value
+			}
+			branch {
+				name
+				project {
+					name
+				}
+			}
+		}
+	}
+`
+
+// buildDetailsValidationsPageSize is the number of validations loaded at once by build show
+const buildDetailsValidationsPageSize = 100
+
+const buildDetailsValidationFragment = `
+	fragment BuildDetailsValidation on Validation {
+		validationStamp {
+			name
+		}
+		validationRuns(count: 1) {
+			creation {
+				time
+				user
+			}
+			lastStatus {
+				statusID {
+					id
+				}
+			}
+		}
+	}
+`
+
+type buildDetails struct {
+	Id            string
+	Name          string
+	DisplayName   string
+	Description   string
+	Creation      client.Signature
+	Branch        buildBranch
+	PromotionRuns []struct {
+		Creation       client.Signature
+		PromotionLevel struct {
+			Name string
+		}
+	}
+	Validations     []buildDetailsValidation
+	UsingQualified  buildDetailsLinks
+	UsedByQualified buildDetailsLinks
+	Properties      []struct {
+		Type struct {
+			TypeName string
+			Name     string
+		}
+		Value json.RawMessage
+	}
+	RunInfo *client.RunInfo
+}
+
+type buildDetailsValidation struct {
+	ValidationStamp struct {
+		Name string
+	}
+	ValidationRuns []struct {
+		Creation   client.Signature
+		LastStatus struct {
+			StatusID struct {
+				Id string
+			}
+		}
+	}
+}
+
+type buildDetailsLinks struct {
+	PageItems []struct {
+		Qualifier string
+		Build     struct {
+			Id              string
+			Name            string
+			ReleaseProperty *struct {
+				Value struct {
+					Name string
+				}
+			}
+			Branch struct {
+				Name    string
+				Project struct {
+					Name string
+				}
+			}
+		}
+	}
+}
+
+// buildDetailsView is the flattened representation of a build used by the outputs of build show
+type buildDetailsView struct {
+	Id          string
+	Name        string
+	DisplayName string
+	Description string `json:",omitempty"`
+	Creation    client.Signature
+	Branch      buildBranch
+	Promotions  []buildPromotionDetailsView
+	Validations []buildValidationDetailsView
+	LinksTo     []buildLinkDetailsView
+	LinksFrom   []buildLinkDetailsView
+	Properties  []buildPropertyDetailsView
+	RunInfo     *client.RunInfo `json:",omitempty"`
+}
+
+type buildPromotionDetailsView struct {
+	Promotion string
+	Creation  client.Signature
+}
+
+type buildValidationDetailsView struct {
+	Stamp    string
+	Status   string
+	Creation client.Signature
+}
+
+type buildLinkDetailsView struct {
+	Project   string
+	Branch    string
+	Id        string
+	Name      string
+	Release   string `json:",omitempty"`
+	Qualifier string `json:",omitempty"`
+}
+
+type buildPropertyDetailsView struct {
+	Type  string
+	Name  string
+	Value json.RawMessage
+}
+
+// loadBuildDetails loads the selected build with all its details, returning a NotFoundError if it does not exist
+func loadBuildDetails(cfg *config.Config, selector buildSelector, links int) (*buildDetails, error) {
+	var data struct {
+		Builds []buildDetails
+	}
+	if err := selector.queryBuild(cfg, `
+		query BuildShow(`+buildSelectorQueryArgs+`, $links: Int!, $validations: Int!) {
+			builds(`+buildSelectorQueryParams+`) {
+				...BuildDetailsFields
+			}
+		}
+	`+buildDetailsFragment+buildDetailsValidationFragment, map[string]interface{}{
+		"links":       links,
+		"validations": buildDetailsValidationsPageSize,
+	}, &data); err != nil {
+		return nil, err
+	}
+	if len(data.Builds) == 0 {
+		return nil, client.NewNotFoundError("%s not found", selector)
+	}
+	build := &data.Builds[0]
+	if err := build.loadRemainingValidations(cfg); err != nil {
+		return nil, err
+	}
+	return build, nil
+}
+
+// loadRemainingValidations loads the validations of the build beyond the first page
+func (b *buildDetails) loadRemainingValidations(cfg *config.Config) error {
+	id, err := strconv.Atoi(b.Id)
+	if err != nil {
+		return err
+	}
+	for page := len(b.Validations); page == buildDetailsValidationsPageSize; {
+		var data struct {
+			Build struct {
+				Validations []buildDetailsValidation
+			}
+		}
+		if err := client.GraphQLCall(cfg, `
+			query BuildShowValidations($id: Int!, $offset: Int!, $size: Int!) {
+				build(id: $id) {
+					validations(offset: $offset, size: $size) {
+						...BuildDetailsValidation
+					}
+				}
+			}
+		`+buildDetailsValidationFragment, map[string]interface{}{
+			"id":     id,
+			"offset": len(b.Validations),
+			"size":   buildDetailsValidationsPageSize,
+		}, &data); err != nil {
+			return err
+		}
+		page = len(data.Build.Validations)
+		b.Validations = append(b.Validations, data.Build.Validations...)
+	}
+	return nil
+}
+
+func (b *buildDetails) view() buildDetailsView {
+	view := buildDetailsView{
+		Id:          b.Id,
+		Name:        b.Name,
+		DisplayName: b.DisplayName,
+		Description: b.Description,
+		Creation:    b.Creation,
+		Branch:      b.Branch,
+		Promotions:  []buildPromotionDetailsView{},
+		Validations: []buildValidationDetailsView{},
+		LinksTo:     b.UsingQualified.view(),
+		LinksFrom:   b.UsedByQualified.view(),
+		Properties:  []buildPropertyDetailsView{},
+		RunInfo:     b.RunInfo,
+	}
+	for _, run := range b.PromotionRuns {
+		view.Promotions = append(view.Promotions, buildPromotionDetailsView{
+			Promotion: run.PromotionLevel.Name,
+			Creation:  run.Creation,
+		})
+	}
+	for _, validation := range b.Validations {
+		if len(validation.ValidationRuns) > 0 {
+			run := validation.ValidationRuns[0]
+			view.Validations = append(view.Validations, buildValidationDetailsView{
+				Stamp:    validation.ValidationStamp.Name,
+				Status:   run.LastStatus.StatusID.Id,
+				Creation: run.Creation,
+			})
+		}
+	}
+	for _, property := range b.Properties {
+		view.Properties = append(view.Properties, buildPropertyDetailsView{
+			Type:  property.Type.TypeName,
+			Name:  property.Type.Name,
+			Value: property.Value,
+		})
+	}
+	return view
+}
+
+func (l buildDetailsLinks) view() []buildLinkDetailsView {
+	links := make([]buildLinkDetailsView, 0, len(l.PageItems))
+	for _, item := range l.PageItems {
+		link := buildLinkDetailsView{
+			Project:   item.Build.Branch.Project.Name,
+			Branch:    item.Build.Branch.Name,
+			Id:        item.Build.Id,
+			Name:      item.Build.Name,
+			Qualifier: item.Qualifier,
+		}
+		if item.Build.ReleaseProperty != nil {
+			link.Release = item.Build.ReleaseProperty.Value.Name
+		}
+		links = append(links, link)
+	}
+	return links
+}
+
+// buildDetailsRows returns the TYPE, NAME and VALUE rows of the table output of build show
+func buildDetailsRows(view buildDetailsView) [][]string {
+	rows := [][]string{
+		{"build", "id", view.Id},
+		{"build", "project", view.Branch.Project.Name},
+		{"build", "branch", view.Branch.Name},
+		{"build", "name", view.Name},
+		{"build", "displayName", view.DisplayName},
+		{"build", "creation", strings.TrimSpace(view.Creation.Time + " " + view.Creation.User)},
+	}
+	if view.Description != "" {
+		rows = append(rows, []string{"build", "description", view.Description})
+	}
+	for _, promotion := range view.Promotions {
+		rows = append(rows, []string{"promotion", promotion.Promotion, promotion.Creation.Time})
+	}
+	for _, validation := range view.Validations {
+		rows = append(rows, []string{"validation", validation.Stamp, validation.Status})
+	}
+	for _, link := range view.LinksTo {
+		rows = append(rows, []string{"link-to", link.String(), link.Qualifier})
+	}
+	for _, link := range view.LinksFrom {
+		rows = append(rows, []string{"link-from", link.String(), link.Qualifier})
+	}
+	for _, property := range view.Properties {
+		rows = append(rows, []string{"property", property.Name, string(property.Value)})
+	}
+	if view.RunInfo != nil {
+		rows = append(rows,
+			[]string{"run-info", "source", strings.TrimSpace(view.RunInfo.SourceType + " " + view.RunInfo.SourceURI)},
+			[]string{"run-info", "trigger", strings.TrimSpace(view.RunInfo.TriggerType + " " + view.RunInfo.TriggerData)},
+			[]string{"run-info", "runTime", fmt.Sprint(view.RunInfo.RunTime)},
+		)
+	}
+	return rows
+}
+
+func (l buildLinkDetailsView) String() string {
+	name := l.Name
+	if l.Release != "" {
+		name = fmt.Sprintf("%s (%s)", l.Name, l.Release)
+	}
+	return fmt.Sprintf("%s/%s/%s", l.Project, l.Branch, name)
+}
+
+func init() {
+	buildCmd.AddCommand(buildShowCmd)
+
+	registerBuildSelectorFlags(buildShowCmd)
+	buildShowCmd.Flags().Int("links", 20, "Maximum number of links to display in each direction")
+}
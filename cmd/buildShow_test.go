@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"yontrack/client"
+	"yontrack/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildDetailsView(t *testing.T) {
+	var build buildDetails
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"id": "9",
+		"name": "42",
+		"creation": {"time": "t9", "user": "ci"},
+		"branch": {"id": "3", "name": "main", "project": {"id": "1", "name": "p1"}},
+		"promotionRuns": [{"creation": {"time": "t10"}, "promotionLevel": {"name": "GOLD"}}],
+		"validations": [
+			{"validationStamp": {"name": "build"}, "validationRuns": [{"creation": {"time": "t11"}, "lastStatus": {"statusID": {"id": "PASSED"}}}]},
+			{"validationStamp": {"name": "deploy"}, "validationRuns": []}
+		],
+		"usingQualified": {"pageItems": [{"qualifier": "", "build": {"id": "20", "name": "7", "releaseProperty": {"value": {"name": "0.1"}}, "branch": {"name": "main", "project": {"name": "dep"}}}}]},
+		"usedByQualified": {"pageItems": [{"qualifier": "tests", "build": {"id": "30", "name": "3", "branch": {"name": "main", "project": {"name": "app"}}}}]},
+		"properties": [{"type": {"typeName": "GitCommitPropertyType", "name": "Git commit"}, "value": {"commit": "abc"}}]
+	}`), &build))
+
+	view := build.view()
+	assert.Equal(t, []buildPromotionDetailsView{{Promotion: "GOLD", Creation: client.Signature{Time: "t10"}}}, view.Promotions)
+	assert.Equal(t, []buildValidationDetailsView{{Stamp: "build", Status: "PASSED", Creation: client.Signature{Time: "t11"}}}, view.Validations)
+	assert.Equal(t, []buildLinkDetailsView{{Project: "dep", Branch: "main", Id: "20", Name: "7", Release: "0.1"}}, view.LinksTo)
+	assert.Equal(t, []buildLinkDetailsView{{Project: "app", Branch: "main", Id: "30", Name: "3", Qualifier: "tests"}}, view.LinksFrom)
+	assert.Nil(t, view.RunInfo)
+
+	assert.Equal(t, [][]string{
+		{"build", "id", "9"},
+		{"build", "project", "p1"},
+		{"build", "branch", "main"},
+		{"build", "name", "42"},
+		{"build", "displayName", ""},
+		{"build", "creation", "t9 ci"},
+		{"promotion", "GOLD", "t10"},
+		{"validation", "build", "PASSED"},
+		{"link-to", "dep/main/7 (0.1)", ""},
+		{"link-from", "app/main/3", "tests"},
+		{"property", "Git commit", `{"commit": "abc"}`},
+	}, buildDetailsRows(view))
+}
+
+func TestBuildSelectorFromCommand(t *testing.T) {
+	parse := func(args ...string) (buildSelector, error) {
+		return buildSelectorFromCommand(newTestCommand(t, registerBuildSelectorFlags, args...))
+	}
+
+	selector, err := parse("--id", "12")
+	require.NoError(t, err)
+	assert.Equal(t, buildSelector{ID: 12}, selector)
+	assert.Equal(t, "build #12", selector.String())
+
+	selector, err = parse("--project", "p1", "--version", "1.2.3")
+	require.NoError(t, err)
+	assert.Equal(t, buildSelector{Project: "p1", Version: "1.2.3"}, selector)
+
+	selector, err = parse("--project", "p1", "--branch", "release/1.0", "--build", "42")
+	require.NoError(t, err)
+	assert.Equal(t, buildSelector{Project: "p1", Branch: "release-1.0", Name: "42"}, selector)
+	assert.Equal(t, "build p1/release-1.0/42", selector.String())
+
+	_, err = parse("--id", "12", "--version", "1.2.3")
+	assert.EqualError(t, err, "--id and --version cannot be used together")
+}
+
+func TestLoadBuildDetails_AllValidations(t *testing.T) {
+	validations := func(count int) []interface{} {
+		page := make([]interface{}, 0, count)
+		for index := 0; index < count; index++ {
+			page = append(page, map[string]interface{}{
+				"validationStamp": map[string]interface{}{"name": fmt.Sprintf("vs-%d", index)},
+				"validationRuns":  []interface{}{map[string]interface{}{"lastStatus": map[string]interface{}{"statusID": map[string]interface{}{"id": "PASSED"}}}},
+			})
+		}
+		return page
+	}
+	var offsets []interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query     string
+			Variables map[string]interface{}
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		var data interface{}
+		if strings.Contains(body.Query, "BuildShowValidations") {
+			offsets = append(offsets, body.Variables["offset"])
+			data = map[string]interface{}{"build": map[string]interface{}{"validations": validations(1)}}
+		} else {
+			assert.Equal(t, float64(buildDetailsValidationsPageSize), body.Variables["validations"])
+			data = map[string]interface{}{"builds": []interface{}{map[string]interface{}{
+				"id":          "9",
+				"name":        "1",
+				"validations": validations(buildDetailsValidationsPageSize),
+			}}}
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{"data": data}))
+	}))
+	defer server.Close()
+
+	build, err := loadBuildDetails(&config.Config{URL: server.URL}, buildSelector{ID: 9}, 10)
+	require.NoError(t, err)
+	assert.Len(t, build.Validations, buildDetailsValidationsPageSize+1)
+	assert.Equal(t, []interface{}{float64(buildDetailsValidationsPageSize)}, offsets)
+}
@@ -110,9 +110,7 @@ func buildUnlink(cmd *cobra.Command) error {
 
 	var data struct {
 		DeleteBuildLinks struct {
-			Errors []struct {
-				Message string
-			}
+			Errors []client.GraphQLError
 		}
 	}
 	if err := client.GraphQLCall(cfg, `
@@ -128,6 +126,8 @@ func buildUnlink(cmd *cobra.Command) error {
 			}) {
 				errors {
 					message
+					exception
+					location
 				}
 			}
 		}
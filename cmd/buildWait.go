@@ -0,0 +1,309 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"yontrack/client"
+	"yontrack/config"
+
+	"github.com/spf13/cobra"
+)
+
+var buildWaitCmd = &cobra.Command{
+	Use:   "wait",
+	Short: "Waits for a build to be promoted or validated",
+	Long: `Waits for a build to be promoted or validated.
+
+	yontrack build wait --project my-project --branch main --build 42 --promotion GOLD
+	yontrack build wait --project my-project --branch main --build 42 --validation deploy --status PASSED
+
+Ontrack is polled every --interval (10s by default) until the conditions are met. The interval is
+multiplied by --backoff after each check, up to --max-interval. The command fails with exit code 7
+when the conditions are not met before --timeout (30m by default), and with exit code 9 as soon as the
+validation reaches one of the --fail-on statuses (FAILED and ERROR by default). A build which does not
+exist yet, as well as network and server errors, are retried until the timeout.
+
+--project defaults to YONTRACK_PROJECT_NAME, --branch to YONTRACK_BRANCH_NAME and --build to
+YONTRACK_BUILD_NAME. The build can also be identified using --id or --version.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		selector, err := buildSelectorFromCommand(cmd)
+		if err != nil {
+			return err
+		}
+		criteria, err := buildWaitCriteriaFromCommand(cmd)
+		if err != nil {
+			return err
+		}
+		options, err := pollOptionsFromCommand(cmd)
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.GetSelectedConfiguration()
+		if err != nil {
+			return err
+		}
+
+		err = poll(options, time.Sleep, time.Now, func() (bool, string, error) {
+			state, err := loadBuildWaitState(cfg, selector, criteria)
+			if err != nil {
+				return pendingOnError(selector, err)
+			}
+			return criteria.evaluate(state)
+		})
+		var gate *gateError
+		if errors.As(err, &gate) {
+			gate.Message = fmt.Sprintf("%s: %s", selector, gate.Message)
+			return gate
+		} else if err != nil {
+			return err
+		}
+		fmt.Printf("%s: %s\n", selector, criteria)
+		return nil
+	},
+}
+
+// buildWaitCriteria defines what to wait for
+type buildWaitCriteria struct {
+	Promotion  string
+	Validation string
+	Status     string
+	FailOn     []string
+}
+
+// buildWaitState is the state of the build, as far as the criteria are concerned
+type buildWaitState struct {
+	Promotions []string
+	// Last status of the validation, empty if not validated yet
+	ValidationStatus string
+}
+
+func buildWaitCriteriaFromCommand(cmd *cobra.Command) (buildWaitCriteria, error) {
+	var criteria buildWaitCriteria
+	var err error
+	if criteria.Promotion, err = cmd.Flags().GetString("promotion"); err != nil {
+		return criteria, err
+	}
+	if criteria.Validation, err = cmd.Flags().GetString("validation"); err != nil {
+		return criteria, err
+	}
+	if criteria.Status, err = cmd.Flags().GetString("status"); err != nil {
+		return criteria, err
+	}
+	if criteria.FailOn, err = cmd.Flags().GetStringSlice("fail-on"); err != nil {
+		return criteria, err
+	}
+	if criteria.Promotion == "" && criteria.Validation == "" {
+		return criteria, errors.New("--promotion and/or --validation is required")
+	}
+	criteria.Status = strings.ToUpper(criteria.Status)
+	for index, status := range criteria.FailOn {
+		criteria.FailOn[index] = strings.ToUpper(status)
+	}
+	return criteria, nil
+}
+
+// evaluate checks if the state of the build satisfies the criteria. A gateError is returned
+// if the validation has reached a failing status.
+func (c buildWaitCriteria) evaluate(state buildWaitState) (bool, string, error) {
+	var pending []string
+	if c.Promotion != "" && !containsString(state.Promotions, c.Promotion) {
+		pending = append(pending, fmt.Sprintf("promotion %s not granted", c.Promotion))
+	}
+	if c.Validation != "" && state.ValidationStatus != c.Status {
+		if containsString(c.FailOn, state.ValidationStatus) {
+			return false, "", &gateError{
+				Message: fmt.Sprintf("validation %s is %s", c.Validation, state.ValidationStatus),
+			}
+		} else if state.ValidationStatus == "" {
+			pending = append(pending, fmt.Sprintf("validation %s not run", c.Validation))
+		} else {
+			pending = append(pending, fmt.Sprintf("validation %s is %s", c.Validation, state.ValidationStatus))
+		}
+	}
+	return len(pending) == 0, strings.Join(pending, ", "), nil
+}
+
+// String describes the criteria once they are met
+func (c buildWaitCriteria) String() string {
+	var items []string
+	if c.Promotion != "" {
+		items = append(items, fmt.Sprintf("promotion %s granted", c.Promotion))
+	}
+	if c.Validation != "" {
+		items = append(items, fmt.Sprintf("validation %s is %s", c.Validation, c.Status))
+	}
+	return strings.Join(items, ", ")
+}
+
+func loadBuildWaitState(cfg *config.Config, selector buildSelector, criteria buildWaitCriteria) (buildWaitState, error) {
+	var state buildWaitState
+	var data struct {
+		Builds []struct {
+			PromotionRuns []struct {
+				PromotionLevel struct {
+					Name string
+				}
+			}
+			Validations []struct {
+				ValidationRuns []struct {
+					LastStatus struct {
+						StatusID struct {
+							Id string
+						}
+					}
+				}
+			}
+		}
+	}
+	var stamps []string
+	if criteria.Validation != "" {
+		stamps = []string{criteria.Validation}
+	}
+	if err := selector.queryBuild(cfg, `
+		query BuildWait(`+buildSelectorQueryArgs+`, $stamps: [String!]) {
+			builds(`+buildSelectorQueryParams+`) {
+				promotionRuns(lastPerLevel: true) {
+					promotionLevel {
+						name
+					}
+				}
+				validations(validationStamps: $stamps) {
+					validationRuns(count: 1) {
+						lastStatus {
+							statusID {
+								id
+							}
+						}
+					}
+				}
+			}
+		}
+	`, map[string]interface{}{
+		"stamps": stamps,
+	}, &data); err != nil {
+		return state, err
+	}
+	if len(data.Builds) == 0 {
+		return state, client.NewNotFoundError("%s not found", selector)
+	}
+	build := data.Builds[0]
+	for _, run := range build.PromotionRuns {
+		state.Promotions = append(state.Promotions, run.PromotionLevel.Name)
+	}
+	if criteria.Validation != "" {
+		for _, validation := range build.Validations {
+			if len(validation.ValidationRuns) > 0 {
+				state.ValidationStatus = validation.ValidationRuns[0].LastStatus.StatusID.Id
+			}
+		}
+	}
+	return state, nil
+}
+
+// pendingOnError keeps waiting when the build does not exist yet or when Ontrack
+// cannot be reached for a while. The other errors stop the wait.
+func pendingOnError(selector buildSelector, err error) (bool, string, error) {
+	if client.IsNotFound(err) {
+		return false, fmt.Sprintf("%s not created yet", selector), nil
+	}
+	switch ExitCode(err) {
+	case ExitNetwork, ExitServer, ExitTimeout:
+		return false, fmt.Sprintf("Ontrack not available (%s)", err), nil
+	default:
+		return false, "", err
+	}
+}
+
+// pollOptions defines how often and how long Ontrack is polled
+type pollOptions struct {
+	Interval    time.Duration
+	MaxInterval time.Duration
+	Backoff     float64
+	Timeout     time.Duration
+}
+
+func pollOptionsFromCommand(cmd *cobra.Command) (pollOptions, error) {
+	var options pollOptions
+	var err error
+	if options.Interval, err = cmd.Flags().GetDuration("interval"); err != nil {
+		return options, err
+	}
+	if options.MaxInterval, err = cmd.Flags().GetDuration("max-interval"); err != nil {
+		return options, err
+	}
+	if options.Backoff, err = cmd.Flags().GetFloat64("backoff"); err != nil {
+		return options, err
+	}
+	if options.Timeout, err = cmd.Flags().GetDuration("timeout"); err != nil {
+		return options, err
+	}
+	if options.Interval <= 0 {
+		return options, errors.New("--interval must be positive")
+	}
+	if options.Backoff < 1 {
+		return options, errors.New("--backoff must be greater than or equal to 1")
+	}
+	return options, nil
+}
+
+// poll calls check until it returns true, an error, or until the timeout is reached.
+// check returns a description of what is still pending, which is displayed between
+// the attempts and used in the timeout error.
+func poll(options pollOptions, sleep func(time.Duration), now func() time.Time, check func() (bool, string, error)) error {
+	deadline := now().Add(options.Timeout)
+	interval := options.Interval
+	for {
+		done, pending, err := check()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+		remaining := deadline.Sub(now())
+		if remaining <= 0 {
+			return &gateError{
+				Message: fmt.Sprintf("%s after %s", pending, options.Timeout),
+				Timeout: true,
+			}
+		}
+		wait := interval
+		if wait > remaining {
+			wait = remaining
+		}
+		_, _ = fmt.Fprintf(os.Stderr, "%s, checking again in %s\n", pending, wait.Truncate(time.Millisecond))
+		sleep(wait)
+		interval = time.Duration(float64(interval) * options.Backoff)
+		if options.MaxInterval > 0 && interval > options.MaxInterval {
+			interval = options.MaxInterval
+		}
+	}
+}
+
+func containsString(items []string, value string) bool {
+	for _, item := range items {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	buildCmd.AddCommand(buildWaitCmd)
+
+	registerBuildSelectorFlags(buildWaitCmd)
+	buildWaitCmd.Flags().String("promotion", "", "Waits for this promotion to be granted")
+	buildWaitCmd.Flags().String("validation", "", "Waits for this validation stamp to reach the --status")
+	buildWaitCmd.Flags().String("status", "PASSED", "Expected status for the validation")
+	buildWaitCmd.Flags().StringSlice("fail-on", []string{"FAILED", "ERROR"}, "Validation statuses which stop the wait with an error")
+	buildWaitCmd.Flags().Duration("interval", 10*time.Second, "Initial interval between two checks")
+	buildWaitCmd.Flags().Duration("max-interval", time.Minute, "Maximum interval between two checks")
+	buildWaitCmd.Flags().Float64("backoff", 1.5, "Factor applied to the interval after each check")
+	buildWaitCmd.Flags().Duration("timeout", 30*time.Minute, "Maximum time to wait")
+}
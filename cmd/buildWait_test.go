@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+	"yontrack/client"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildWaitCriteria_Evaluate(t *testing.T) {
+	criteria := buildWaitCriteria{
+		Promotion:  "GOLD",
+		Validation: "deploy",
+		Status:     "PASSED",
+		FailOn:     []string{"FAILED", "ERROR"},
+	}
+
+	done, pending, err := criteria.evaluate(buildWaitState{})
+	require.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, "promotion GOLD not granted, validation deploy not run", pending)
+
+	done, pending, err = criteria.evaluate(buildWaitState{Promotions: []string{"GOLD"}, ValidationStatus: "WARNING"})
+	require.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, "validation deploy is WARNING", pending)
+
+	done, _, err = criteria.evaluate(buildWaitState{Promotions: []string{"SILVER", "GOLD"}, ValidationStatus: "PASSED"})
+	require.NoError(t, err)
+	assert.True(t, done)
+
+	_, _, err = criteria.evaluate(buildWaitState{ValidationStatus: "FAILED"})
+	assert.Equal(t, &gateError{Message: "validation deploy is FAILED"}, err)
+	assert.Equal(t, ExitGate, ExitCode(err))
+}
+
+type fakeClock struct {
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func (c *fakeClock) sleep(d time.Duration) {
+	c.sleeps = append(c.sleeps, d)
+	c.now = c.now.Add(d)
+}
+
+func (c *fakeClock) time() time.Time {
+	return c.now
+}
+
+func TestPoll_Backoff(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	checks := 0
+	err := poll(pollOptions{
+		Interval:    time.Second,
+		MaxInterval: 3 * time.Second,
+		Backoff:     2,
+		Timeout:     time.Minute,
+	}, clock.sleep, clock.time, func() (bool, string, error) {
+		checks++
+		return checks == 5, "pending", nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second}, clock.sleeps)
+}
+
+func TestPoll_Timeout(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	err := poll(pollOptions{
+		Interval: 4 * time.Second,
+		Backoff:  1,
+		Timeout:  10 * time.Second,
+	}, clock.sleep, clock.time, func() (bool, string, error) {
+		return false, "promotion GOLD not granted", nil
+	})
+	assert.Equal(t, &gateError{Message: "promotion GOLD not granted after 10s", Timeout: true}, err)
+	assert.Equal(t, ExitTimeout, ExitCode(err))
+	assert.Equal(t, []time.Duration{4 * time.Second, 4 * time.Second, 2 * time.Second}, clock.sleeps)
+}
+
+func TestPendingOnError(t *testing.T) {
+	selector := buildSelector{Project: "p", Branch: "main", Name: "42"}
+
+	done, pending, err := pendingOnError(selector, client.NewNotFoundError("%s not found", selector))
+	require.NoError(t, err)
+	assert.False(t, done)
+	assert.Contains(t, pending, "not created yet")
+
+	for _, transient := range []error{
+		&url.Error{Op: "Post", URL: "http://localhost", Err: errors.New("connection refused")},
+		&client.HTTPError{StatusCode: 503, Message: "503 Service Unavailable"},
+		&client.HTTPError{StatusCode: 504, Message: "504 Gateway Timeout"},
+	} {
+		done, pending, err = pendingOnError(selector, transient)
+		require.NoError(t, err)
+		assert.False(t, done)
+		assert.Contains(t, pending, "Ontrack not available")
+	}
+
+	_, _, err = pendingOnError(selector, &client.HTTPError{StatusCode: 401, Message: "401 Unauthorized"})
+	assert.Error(t, err)
+}
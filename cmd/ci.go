@@ -18,8 +18,11 @@ be changed using the --file option:
 
 	yontrack ci config --file .yontrack/ci.yaml
 
-By default, no environment variable is passed to the configuration, for obvious security reasons.
-They need to be passed explicitly using the --env options:
+The variables set by the CI engine (Jenkins, GitHub Actions, GitLab CI, Bitbucket Pipelines, Azure
+Pipelines or CircleCI), selected by --ci or detected, are passed to the configuration. Only the
+variables known to be relevant for the engine are passed, never its secrets (--ci-env=false to disable).
+
+Any other environment variable needs to be passed explicitly using the --env options:
 
 	yontrack ci config \
 	  --env GIT_URL=git@github.com:nemerosa/ontrack.git \
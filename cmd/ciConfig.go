@@ -1,11 +1,11 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"yontrack/client"
 	"yontrack/config"
+	"yontrack/output"
 	"yontrack/utils"
 
 	"github.com/spf13/cobra"
@@ -16,36 +16,76 @@ type CIConfigContext struct {
 	ConfigContent string
 	EnvVars       map[string]string
 	EnvList       []map[string]interface{}
-	Output        string
+	Output        *output.Printer
 	CI            string
 	SCM           string
 }
 
+// ciDiagnostics prints the diagnostics of the CI commands on stderr, masking the secrets
+type ciDiagnostics struct {
+	quiet   bool
+	verbose bool
+}
+
+func ciDiagnosticsFromCommand(cmd *cobra.Command) (ciDiagnostics, error) {
+	var d ciDiagnostics
+	var err error
+	if d.quiet, err = cmd.Flags().GetBool("quiet"); err != nil {
+		return d, err
+	}
+	if d.verbose, err = cmd.Flags().GetBool("verbose"); err != nil {
+		return d, err
+	}
+	if d.quiet && d.verbose {
+		return d, fmt.Errorf("--quiet and --verbose cannot be used together")
+	}
+	return d, nil
+}
+
+// printf prints a diagnostic line, unless --quiet is set
+func (d ciDiagnostics) printf(format string, args ...interface{}) {
+	if !d.quiet {
+		_, _ = fmt.Fprint(os.Stderr, utils.Secrets.Mask(fmt.Sprintf(format, args...)))
+	}
+}
+
+// verbosef prints a diagnostic line only if --verbose is set
+func (d ciDiagnostics) verbosef(format string, args ...interface{}) {
+	if d.verbose {
+		d.printf(format, args...)
+	}
+}
+
 func getCIConfigContext(cmd *cobra.Command) (*CIConfigContext, error) {
+	diagnostics, err := ciDiagnosticsFromCommand(cmd)
+	if err != nil {
+		return nil, err
+	}
+
 	file, err := cmd.Flags().GetString("file")
 	if err != nil {
 		return nil, err
 	}
 
-	_, _ = fmt.Fprintf(os.Stderr, "File: %s\n", file)
+	diagnostics.printf("File: %s\n", file)
 
 	ci, err := cmd.Flags().GetString("ci")
 	if err != nil {
 		return nil, err
 	}
-	_, _ = fmt.Fprintf(os.Stderr, "CI: %s\n", ci)
+	diagnostics.printf("CI: %s\n", ci)
 
 	scm, err := cmd.Flags().GetString("scm")
 	if err != nil {
 		return nil, err
 	}
-	_, _ = fmt.Fprintf(os.Stderr, "SCM: %s\n", scm)
+	diagnostics.printf("SCM: %s\n", scm)
 
-	output, err := cmd.Flags().GetString("output")
+	printer, err := output.FromCommand(cmd)
 	if err != nil {
 		return nil, err
 	}
-	_, _ = fmt.Fprintf(os.Stderr, "Output: %s\n", output)
+	diagnostics.printf("Output: %s\n", printer.Format)
 
 	// Env vars from a file
 	envFile, err := cmd.Flags().GetString("env-file")
@@ -53,8 +93,22 @@ func getCIConfigContext(cmd *cobra.Command) (*CIConfigContext, error) {
 		return nil, err
 	}
 
-	// Start with env vars from file (if provided)
+	// Start with the variables of the CI profile (if any)
 	envVars := make(map[string]string)
+	ciEnv, err := cmd.Flags().GetBool("ci-env")
+	if err != nil {
+		return nil, err
+	}
+	if ciEnv {
+		if profile := ciProfile(ci); profile != nil {
+			diagnostics.printf("CI profile: %s\n", profile.Name)
+			for key, value := range profile.Collect(os.LookupEnv) {
+				envVars[key] = value
+			}
+		}
+	}
+
+	// Then env vars from file (if provided)
 	if envFile != "" {
 		fileEnvVars, err := utils.ReadEnvFile(envFile)
 		if err != nil {
@@ -98,9 +152,10 @@ func getCIConfigContext(cmd *cobra.Command) (*CIConfigContext, error) {
 		envVars[key] = value
 	}
 
-	// Print the env variables
+	// Print the env variables, hiding the secrets
+	utils.Secrets.AddEnv(envVars)
 	for key, value := range envVars {
-		_, _ = fmt.Fprintf(os.Stderr, "Env: %s=%s\n", key, value)
+		diagnostics.printf("Env: %s=%s\n", key, utils.Secrets.MaskValue(key, value))
 	}
 
 	// Convert envVars map to a list of CIEnv objects
@@ -117,6 +172,7 @@ func getCIConfigContext(cmd *cobra.Command) (*CIConfigContext, error) {
 	if err != nil {
 		return nil, err
 	}
+	utils.Secrets.AddEnv(cmdVars)
 
 	// Reading the configuration file
 	contentBytes, err := os.ReadFile(file)
@@ -124,7 +180,17 @@ func getCIConfigContext(cmd *cobra.Command) (*CIConfigContext, error) {
 		return nil, fmt.Errorf("failed to read configuration file: %w", err)
 	}
 	initialConfigContent := string(contentBytes)
-	configExpanded, err := utils.ExpandConfig(initialConfigContent)
+	includeCache, err := cmd.Flags().GetString("include-cache")
+	if err != nil {
+		return nil, err
+	}
+	if includeCache == "" {
+		includeCache = utils.DefaultIncludeCacheDir()
+	}
+	configExpanded, err := utils.ExpandConfigWithOptions(initialConfigContent, utils.ExpandOptions{
+		Path:     file,
+		CacheDir: includeCache,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to expand configuration: %w", err)
 	}
@@ -133,18 +199,26 @@ func getCIConfigContext(cmd *cobra.Command) (*CIConfigContext, error) {
 		return nil, fmt.Errorf("failed to render configuration as a template: %w", err)
 	}
 
-	_, _ = fmt.Fprintf(os.Stderr, "Configuration content:\n%s\n", configContent)
+	diagnostics.verbosef("Configuration content:\n%s\n", configContent)
 
 	return &CIConfigContext{
 		ConfigContent: configContent,
 		EnvVars:       envVars,
 		EnvList:       envList,
-		Output:        output,
+		Output:        printer,
 		CI:            ci,
 		SCM:           scm,
 	}, nil
 }
 
+// ciProfile returns the CI profile selected by --ci or, when not set, the one of the detected CI engine
+func ciProfile(ci string) *utils.CIProfile {
+	if ci != "" {
+		return utils.GetCIProfile(ci)
+	}
+	return utils.DetectCIProfile(os.LookupEnv)
+}
+
 var ciConfigCmd = &cobra.Command{
 	Use:   "config",
 	Short: "Injection of CI configuration",
@@ -159,17 +233,30 @@ be changed using the --file option:
 
 	yontrack ci config --file .yontrack/ci.yaml
 
-By default, no environment variable is passed to the configuration, for obvious security reasons.
-They need to be passed explicitly using the --env options:
+The variables set by the CI engine (Jenkins, GitHub Actions, GitLab CI, Bitbucket Pipelines, Azure
+Pipelines or CircleCI), selected by --ci or detected, are passed to the configuration. Only the
+variables known to be relevant for the engine are passed, never its secrets (--ci-env=false to disable).
+
+Any other environment variable needs to be passed explicitly using the --env options:
 
 	yontrack ci config \
 	  --env GIT_URL=git@github.com:nemerosa/ontrack.git \
 	  --env GIT_BRANCH=release/5.0
 
+With --dry-run, nothing is created. The rendered configuration is displayed, together with
+the effective project, branch and build, and what would be created or updated in Ontrack:
+
+	yontrack ci config --dry-run --env GIT_BRANCH=release/5.0
+
 See the Yontrack documentation for the format of the YAML configuration and the list of needed
 environment variables.
 `,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return err
+		}
+
 		ciContext, err := getCIConfigContext(cmd)
 		if err != nil {
 			return err
@@ -181,6 +268,10 @@ environment variables.
 			return err
 		}
 
+		if dryRun {
+			return ciConfigDryRun(config, ciContext)
+		}
+
 		// Returned data
 		var data struct {
 			ConfigureBuild struct {
@@ -198,9 +289,7 @@ environment variables.
 						}
 					}
 				}
-				Errors []struct {
-					Message string
-				}
+				Errors []client.GraphQLError
 			}
 		}
 
@@ -220,6 +309,7 @@ environment variables.
                         errors {
                             message
                             exception
+                            location
                         }
                         build {
                             id
@@ -252,34 +342,25 @@ environment variables.
 		}
 
 		// Output
-		if ciContext.Output != "" {
-			build := data.ConfigureBuild.Build
-			if ciContext.Output == "env" {
-				_, _ = fmt.Fprintf(os.Stdout, "export YONTRACK_PROJECT_ID=%s\n", build.Branch.Project.ID)
-				_, _ = fmt.Fprintf(os.Stdout, "export YONTRACK_PROJECT_NAME=%s\n", build.Branch.Project.Name)
-				_, _ = fmt.Fprintf(os.Stdout, "export YONTRACK_BRANCH_ID=%s\n", build.Branch.ID)
-				_, _ = fmt.Fprintf(os.Stdout, "export YONTRACK_BRANCH_NAME=%s\n", build.Branch.Name)
-				_, _ = fmt.Fprintf(os.Stdout, "export YONTRACK_BUILD_ID=%s\n", build.ID)
-				_, _ = fmt.Fprintf(os.Stdout, "export YONTRACK_BUILD_NAME=%s\n", build.Name)
-			} else if ciContext.Output == "json" {
-				jsonBytes, err := json.MarshalIndent(build, "", "  ")
-				if err != nil {
-					return fmt.Errorf("failed to marshal build to JSON: %w", err)
-				}
-				_, _ = fmt.Fprintf(os.Stdout, "%s\n", jsonBytes)
-			} else {
-				return fmt.Errorf("unsupported output type %s", ciContext.Output)
-			}
-		}
-
-		// OK
-		return nil
+		build := data.ConfigureBuild.Build
+		return ciContext.Output.Print(&output.Result{
+			Value:   build,
+			Headers: []string{"PROJECT", "BRANCH", "ID", "BUILD"},
+			Rows:    [][]string{{build.Branch.Project.Name, build.Branch.Name, build.ID, build.Name}},
+			Env: buildEnvVariables(
+				build.Branch.Project.ID, build.Branch.Project.Name,
+				build.Branch.ID, build.Branch.Name,
+				build.ID, build.Name,
+			),
+		})
 	},
 }
 
 func init() {
 	ciCmd.AddCommand(ciConfigCmd)
 	registerCIConfigFlags(ciConfigCmd)
+	output.RegisterSinkFlags(ciConfigCmd)
+	ciConfigCmd.Flags().Bool("dry-run", false, "Displays the effective configuration and what would be created, without changing anything in Ontrack")
 }
 
 func registerCIConfigFlags(cmd *cobra.Command) {
@@ -287,9 +368,12 @@ func registerCIConfigFlags(cmd *cobra.Command) {
 	cmd.Flags().StringSliceP("env", "e", []string{}, "Environment variables in KEY=VALUE format (can be used multiple times)")
 	cmd.Flags().StringSlice("env-all", []string{}, "Uses the specified prefix to select environment variables to inject.")
 	cmd.Flags().String("env-file", "", "Path to an env file containing key/values (one per line, using the KEY=VALUE format)")
-	cmd.Flags().String("ci", "", "ID of the CI engine to use. If not specified, Yontrack will try to guess it based on the provided environment variables.")
+	cmd.Flags().String("ci", "", "ID of the CI engine to use (jenkins, github, gitlab, bitbucket, azure, circleci...). If not specified, Yontrack will try to guess it based on the provided environment variables.")
+	cmd.Flags().Bool("ci-env", true, "Passes the variables of the CI engine (selected by --ci or detected) to the configuration. Use --ci-env=false to disable.")
 	cmd.Flags().String("scm", "", "ID of the SCM engine to use. If not specified, Yontrack will try to guess it based on the provided environment variables.")
-	cmd.Flags().StringP("output", "o", "", "Output of the command: env, json.")
+	cmd.Flags().String("include-cache", "", "Directory containing the copies of the remote (http:// and https://) includes of the configuration file. Defaults to YONTRACK_INCLUDE_CACHE or to yontrack/includes in the user cache directory.")
+	cmd.Flags().BoolP("quiet", "q", false, "Does not print any diagnostic on stderr")
+	cmd.Flags().Bool("verbose", false, "Prints the rendered configuration on stderr, in addition to the other diagnostics")
 	cmd.Flags().StringSliceP("var", "v", []string{}, "Arbitrary variables in KEY=VALUE format to pass to the evaluation of the configuration file as a Go template. Each variable is accessed from the `vars` scope.")
 }
 
@@ -1,11 +1,9 @@
 package cmd
 
 import (
-	"encoding/json"
-	"fmt"
-	"os"
 	"yontrack/client"
 	"yontrack/config"
+	"yontrack/output"
 
 	"github.com/spf13/cobra"
 )
@@ -24,8 +22,11 @@ be changed using the --file option:
 
 	yontrack ci config-branch --file .yontrack/ci.yaml
 
-By default, no environment variable is passed to the configuration, for obvious security reasons.
-They need to be passed explicitly using the --env options:
+The variables set by the CI engine (Jenkins, GitHub Actions, GitLab CI, Bitbucket Pipelines, Azure
+Pipelines or CircleCI), selected by --ci or detected, are passed to the configuration. Only the
+variables known to be relevant for the engine are passed, never its secrets (--ci-env=false to disable).
+
+Any other environment variable needs to be passed explicitly using the --env options:
 
 	yontrack ci config-branch \
 	  --env GIT_URL=git@github.com:nemerosa/ontrack.git \
@@ -58,9 +59,7 @@ environment variables.
 						Name string
 					}
 				}
-				Errors []struct {
-					Message string
-				}
+				Errors []client.GraphQLError
 			}
 		}
 
@@ -80,6 +79,7 @@ environment variables.
                         errors {
                             message
                             exception
+                            location
                         }
                         branch {
 							id
@@ -107,30 +107,18 @@ environment variables.
 		}
 
 		// Output
-		if ciContext.Output != "" {
-			branch := data.ConfigureBranch.Branch
-			if ciContext.Output == "env" {
-				_, _ = fmt.Fprintf(os.Stdout, "export YONTRACK_PROJECT_ID=%s\n", branch.Project.ID)
-				_, _ = fmt.Fprintf(os.Stdout, "export YONTRACK_PROJECT_NAME=%s\n", branch.Project.Name)
-				_, _ = fmt.Fprintf(os.Stdout, "export YONTRACK_BRANCH_ID=%s\n", branch.ID)
-				_, _ = fmt.Fprintf(os.Stdout, "export YONTRACK_BRANCH_NAME=%s\n", branch.Name)
-			} else if ciContext.Output == "json" {
-				jsonBytes, err := json.MarshalIndent(branch, "", "  ")
-				if err != nil {
-					return fmt.Errorf("failed to marshal branch to JSON: %w", err)
-				}
-				_, _ = fmt.Fprintf(os.Stdout, "%s\n", jsonBytes)
-			} else {
-				return fmt.Errorf("unsupported output type %s", ciContext.Output)
-			}
-		}
-
-		// OK
-		return nil
+		branch := data.ConfigureBranch.Branch
+		return ciContext.Output.Print(&output.Result{
+			Value:   branch,
+			Headers: []string{"PROJECT", "ID", "BRANCH"},
+			Rows:    [][]string{{branch.Project.Name, branch.ID, branch.Name}},
+			Env:     branchEnvVariables(branch.Project.ID, branch.Project.Name, branch.ID, branch.Name),
+		})
 	},
 }
 
 func init() {
 	ciCmd.AddCommand(ciConfigBranchCmd)
 	registerCIConfigFlags(ciConfigBranchCmd)
+	output.RegisterSinkFlags(ciConfigBranchCmd)
 }
@@ -0,0 +1,317 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"yontrack/client"
+	"yontrack/config"
+	"yontrack/output"
+	"yontrack/utils"
+)
+
+// ciEffectiveConfiguration is what Ontrack would create or update for a CI configuration
+type ciEffectiveConfiguration struct {
+	Project ciEffectiveEntity `json:"project"`
+	Branch  ciEffectiveBranch `json:"branch"`
+	Build   ciEffectiveEntity `json:"build"`
+}
+
+type ciEffectiveEntity struct {
+	Name       string       `json:"name"`
+	Properties []ciProperty `json:"properties"`
+}
+
+type ciEffectiveBranch struct {
+	Name        string       `json:"name"`
+	Properties  []ciProperty `json:"properties"`
+	Validations []string     `json:"validations"`
+	Promotions  []string     `json:"promotions"`
+}
+
+type ciProperty struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// ciCurrentState is the state on the server of the entities targeted by a CI configuration
+type ciCurrentState struct {
+	ProjectExists     bool
+	ProjectProperties []ciProperty
+	BranchExists      bool
+	BranchProperties  []ciProperty
+	Validations       []string
+	Promotions        []string
+	BuildExists       bool
+	BuildProperties   []ciProperty
+}
+
+// ciConfigChange is one difference between the effective configuration and the current state
+type ciConfigChange struct {
+	// One of create, update or unchanged
+	Change string `json:"change"`
+	// project, branch or build
+	Entity string `json:"entity"`
+	// project, branch, build, property, validation or promotion
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// ciConfigDryRunResult is the output of ci config --dry-run
+type ciConfigDryRunResult struct {
+	Configuration string                   `json:"configuration"`
+	Effective     ciEffectiveConfiguration `json:"effective"`
+	Changes       []ciConfigChange         `json:"changes"`
+}
+
+var ciChangeSymbols = map[string]string{
+	"create":    "+",
+	"update":    "~",
+	"unchanged": "=",
+}
+
+// ciConfigDryRun computes the effective configuration without creating anything and
+// prints it, together with its differences with the current state in Ontrack
+func ciConfigDryRun(cfg *config.Config, ciContext *CIConfigContext) error {
+	if ciContext.Output.Sink != nil {
+		return fmt.Errorf("--output-file cannot be used with --dry-run")
+	}
+	effective, err := loadEffectiveCIConfiguration(cfg, ciContext)
+	if err != nil {
+		return err
+	}
+	state, err := loadCICurrentState(cfg, effective)
+	if err != nil {
+		return err
+	}
+	result := ciConfigDryRunResult{
+		Configuration: utils.Secrets.Mask(ciContext.ConfigContent),
+		Effective:     *effective,
+		Changes:       diffCIConfiguration(*effective, state),
+	}
+
+	rows := make([][]string, 0, len(result.Changes))
+	for _, change := range result.Changes {
+		rows = append(rows, []string{change.Change, change.Entity, change.Kind, change.Name})
+	}
+	return ciContext.Output.Print(&output.Result{
+		Value:   result,
+		Headers: []string{"CHANGE", "ENTITY", "KIND", "NAME"},
+		Rows:    rows,
+		Plain: func(w io.Writer) error {
+			_, _ = fmt.Fprintf(w, "Rendered configuration:\n%s\n", result.Configuration)
+			_, _ = fmt.Fprintf(w, "Effective configuration:\n")
+			_, _ = fmt.Fprintf(w, "  project: %s\n", effective.Project.Name)
+			_, _ = fmt.Fprintf(w, "  branch: %s\n", effective.Branch.Name)
+			_, _ = fmt.Fprintf(w, "  build: %s\n", effective.Build.Name)
+			_, _ = fmt.Fprintf(w, "\nChanges (dry run, nothing has been created):\n")
+			for _, change := range result.Changes {
+				if change.Kind == change.Entity {
+					_, _ = fmt.Fprintf(w, "  %s %s %s\n", ciChangeSymbols[change.Change], change.Kind, change.Name)
+				} else {
+					_, _ = fmt.Fprintf(w, "  %s %s %s %s\n", ciChangeSymbols[change.Change], change.Entity, change.Kind, change.Name)
+				}
+			}
+			return nil
+		},
+	})
+}
+
+func loadEffectiveCIConfiguration(cfg *config.Config, ciContext *CIConfigContext) (*ciEffectiveConfiguration, error) {
+	// effectiveCIConfiguration returns a JSON scalar
+	var data struct {
+		EffectiveCIConfiguration json.RawMessage
+	}
+	if err := client.GraphQLCall(cfg, `
+		query OntrackCliCIConfigDryRun(
+			$config: String!,
+			$ci: String,
+			$scm: String,
+			$env: [CIEnv!]!,
+		) {
+			effectiveCIConfiguration(input: {
+				config: $config,
+				ci: $ci,
+				scm: $scm,
+				env: $env,
+			})
+		}
+	`, map[string]interface{}{
+		"config": ciContext.ConfigContent,
+		"ci":     ciContext.CI,
+		"scm":    ciContext.SCM,
+		"env":    ciContext.EnvList,
+	}, &data); err != nil {
+		return nil, err
+	}
+	if len(data.EffectiveCIConfiguration) == 0 || string(data.EffectiveCIConfiguration) == "null" {
+		return nil, fmt.Errorf("no effective CI configuration returned by Ontrack")
+	}
+	var effective ciEffectiveConfiguration
+	if err := json.Unmarshal(data.EffectiveCIConfiguration, &effective); err != nil {
+		return nil, fmt.Errorf("cannot parse the effective CI configuration returned by Ontrack: %w", err)
+	}
+	return &effective, nil
+}
+
+type ciStateProperties []struct {
+	Type struct {
+		TypeName string
+	}
+	Value json.RawMessage
+}
+
+func (p ciStateProperties) properties() []ciProperty {
+	properties := make([]ciProperty, 0, len(p))
+	for _, property := range p {
+		properties = append(properties, ciProperty{Type: property.Type.TypeName, Value: property.Value})
+	}
+	return properties
+}
+
+func loadCICurrentState(cfg *config.Config, effective *ciEffectiveConfiguration) (ciCurrentState, error) {
+	var state ciCurrentState
+	var data struct {
+		Projects []struct {
+			Properties ciStateProperties
+		}
+		Branches []struct {
+			Properties       ciStateProperties
+			ValidationStamps []struct {
+				Name string
+			}
+			PromotionLevels []struct {
+				Name string
+			}
+		}
+		Builds []struct {
+			Properties ciStateProperties
+		}
+	}
+	if err := client.GraphQLCall(cfg, `
+		query OntrackCliCIConfigState($project: String!, $branch: String!, $branchName: String!, $build: String!) {
+			projects(name: $project) {
+				properties(hasValue: true) {
+					type {
+						typeName
+					}
+					value
+				}
+			}
+			branches(project: $project, name: $branch) {
+				properties(hasValue: true) {
+					type {
+						typeName
+					}
+					value
+				}
+				validationStamps {
+					name
+				}
+				promotionLevels {
+					name
+				}
+			}
+			builds(project: $project, branch: $branchName, name: $build) {
+				properties(hasValue: true) {
+					type {
+						typeName
+					}
+					value
+				}
+			}
+		}
+	`, map[string]interface{}{
+		"project":    effective.Project.Name,
+		"branch":     "^" + regexp.QuoteMeta(effective.Branch.Name) + "$",
+		"branchName": effective.Branch.Name,
+		"build":      effective.Build.Name,
+	}, &data); err != nil {
+		return state, err
+	}
+	if len(data.Projects) > 0 {
+		state.ProjectExists = true
+		state.ProjectProperties = data.Projects[0].Properties.properties()
+	}
+	if len(data.Branches) > 0 {
+		branch := data.Branches[0]
+		state.BranchExists = true
+		state.BranchProperties = branch.Properties.properties()
+		for _, stamp := range branch.ValidationStamps {
+			state.Validations = append(state.Validations, stamp.Name)
+		}
+		for _, level := range branch.PromotionLevels {
+			state.Promotions = append(state.Promotions, level.Name)
+		}
+	}
+	if len(data.Builds) > 0 {
+		state.BuildExists = true
+		state.BuildProperties = data.Builds[0].Properties.properties()
+	}
+	return state, nil
+}
+
+// diffCIConfiguration lists what would be created or updated in Ontrack to reach the effective configuration
+func diffCIConfiguration(effective ciEffectiveConfiguration, state ciCurrentState) []ciConfigChange {
+	var changes []ciConfigChange
+	entity := func(entity string, name string, exists bool) {
+		changes = append(changes, ciConfigChange{Change: ciChange(exists), Entity: entity, Kind: entity, Name: name})
+	}
+	properties := func(entity string, expected []ciProperty, actual []ciProperty) {
+		for _, property := range expected {
+			change := "create"
+			for _, current := range actual {
+				if current.Type == property.Type {
+					if sameJSON(current.Value, property.Value) {
+						change = "unchanged"
+					} else {
+						change = "update"
+					}
+				}
+			}
+			changes = append(changes, ciConfigChange{Change: change, Entity: entity, Kind: "property", Name: property.Type})
+		}
+	}
+
+	entity("project", effective.Project.Name, state.ProjectExists)
+	properties("project", effective.Project.Properties, state.ProjectProperties)
+	entity("branch", effective.Branch.Name, state.BranchExists)
+	properties("branch", effective.Branch.Properties, state.BranchProperties)
+	for _, validation := range effective.Branch.Validations {
+		changes = append(changes, ciConfigChange{
+			Change: ciChange(containsString(state.Validations, validation)),
+			Entity: "branch",
+			Kind:   "validation",
+			Name:   validation,
+		})
+	}
+	for _, promotion := range effective.Branch.Promotions {
+		changes = append(changes, ciConfigChange{
+			Change: ciChange(containsString(state.Promotions, promotion)),
+			Entity: "branch",
+			Kind:   "promotion",
+			Name:   promotion,
+		})
+	}
+	entity("build", effective.Build.Name, state.BuildExists)
+	properties("build", effective.Build.Properties, state.BuildProperties)
+	return changes
+}
+
+func ciChange(exists bool) string {
+	if exists {
+		return "unchanged"
+	}
+	return "create"
+}
+
+// sameJSON checks if two JSON values are equal, regardless of their formatting and of the order of the keys
+func sameJSON(a json.RawMessage, b json.RawMessage) bool {
+	var x, y interface{}
+	if json.Unmarshal(a, &x) != nil || json.Unmarshal(b, &y) != nil {
+		return string(a) == string(b)
+	}
+	return reflect.DeepEqual(x, y)
+}
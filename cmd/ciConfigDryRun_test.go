@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"yontrack/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffCIConfiguration(t *testing.T) {
+	effective := ciEffectiveConfiguration{
+		Project: ciEffectiveEntity{
+			Name: "p1",
+			Properties: []ciProperty{
+				{Type: "GitHubProjectConfigurationPropertyType", Value: json.RawMessage(`{"repository": "org/p1", "configuration": "github"}`)},
+			},
+		},
+		Branch: ciEffectiveBranch{
+			Name: "release-5.0",
+			Properties: []ciProperty{
+				{Type: "GitBranchConfigurationPropertyType", Value: json.RawMessage(`{"branch": "release/5.0"}`)},
+			},
+			Validations: []string{"build", "deploy"},
+			Promotions:  []string{"BRONZE"},
+		},
+		Build: ciEffectiveEntity{
+			Name: "42",
+			Properties: []ciProperty{
+				{Type: "GitCommitPropertyType", Value: json.RawMessage(`{"commit": "abc"}`)},
+			},
+		},
+	}
+
+	t.Run("nothing exists", func(t *testing.T) {
+		assert.Equal(t, []ciConfigChange{
+			{Change: "create", Entity: "project", Kind: "project", Name: "p1"},
+			{Change: "create", Entity: "project", Kind: "property", Name: "GitHubProjectConfigurationPropertyType"},
+			{Change: "create", Entity: "branch", Kind: "branch", Name: "release-5.0"},
+			{Change: "create", Entity: "branch", Kind: "property", Name: "GitBranchConfigurationPropertyType"},
+			{Change: "create", Entity: "branch", Kind: "validation", Name: "build"},
+			{Change: "create", Entity: "branch", Kind: "validation", Name: "deploy"},
+			{Change: "create", Entity: "branch", Kind: "promotion", Name: "BRONZE"},
+			{Change: "create", Entity: "build", Kind: "build", Name: "42"},
+			{Change: "create", Entity: "build", Kind: "property", Name: "GitCommitPropertyType"},
+		}, diffCIConfiguration(effective, ciCurrentState{}))
+	})
+
+	t.Run("existing branch", func(t *testing.T) {
+		state := ciCurrentState{
+			ProjectExists: true,
+			ProjectProperties: []ciProperty{
+				{Type: "GitHubProjectConfigurationPropertyType", Value: json.RawMessage(`{"configuration":"github","repository":"org/p1"}`)},
+			},
+			BranchExists: true,
+			BranchProperties: []ciProperty{
+				{Type: "GitBranchConfigurationPropertyType", Value: json.RawMessage(`{"branch": "release/4.0"}`)},
+			},
+			Validations: []string{"build"},
+			Promotions:  []string{"BRONZE", "SILVER"},
+		}
+		assert.Equal(t, []ciConfigChange{
+			{Change: "unchanged", Entity: "project", Kind: "project", Name: "p1"},
+			{Change: "unchanged", Entity: "project", Kind: "property", Name: "GitHubProjectConfigurationPropertyType"},
+			{Change: "unchanged", Entity: "branch", Kind: "branch", Name: "release-5.0"},
+			{Change: "update", Entity: "branch", Kind: "property", Name: "GitBranchConfigurationPropertyType"},
+			{Change: "unchanged", Entity: "branch", Kind: "validation", Name: "build"},
+			{Change: "create", Entity: "branch", Kind: "validation", Name: "deploy"},
+			{Change: "unchanged", Entity: "branch", Kind: "promotion", Name: "BRONZE"},
+			{Change: "create", Entity: "build", Kind: "build", Name: "42"},
+			{Change: "create", Entity: "build", Kind: "property", Name: "GitCommitPropertyType"},
+		}, diffCIConfiguration(effective, state))
+	})
+}
+
+func TestLoadEffectiveCIConfiguration(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query     string
+			Variables map[string]interface{}
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Contains(t, body.Query, "effectiveCIConfiguration(input: {")
+		assert.NotContains(t, body.Query, "project {", "JSON scalar without selection set")
+		assert.Equal(t, map[string]interface{}{
+			"config": "version: v1\n",
+			"ci":     "github",
+			"scm":    "",
+			"env":    []interface{}{map[string]interface{}{"name": "GITHUB_REF", "value": "refs/heads/main"}},
+		}, body.Variables)
+		_, _ = w.Write([]byte(`{"data":{"effectiveCIConfiguration":{
+			"project":{"name":"p1","properties":[{"type":"GitHubProjectConfigurationPropertyType","value":{"repository":"org/p1"}}]},
+			"branch":{"name":"main","properties":[],"validations":["build","deploy"],"promotions":["BRONZE"]},
+			"build":{"name":"42","properties":[{"type":"GitCommitPropertyType","value":{"commit":"abc"}}]}
+		}}}`))
+	}))
+	defer server.Close()
+
+	effective, err := loadEffectiveCIConfiguration(&config.Config{URL: server.URL}, &CIConfigContext{
+		ConfigContent: "version: v1\n",
+		CI:            "github",
+		EnvList:       []map[string]interface{}{{"name": "GITHUB_REF", "value": "refs/heads/main"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "p1", effective.Project.Name)
+	assert.Equal(t, "GitHubProjectConfigurationPropertyType", effective.Project.Properties[0].Type)
+	assert.JSONEq(t, `{"repository":"org/p1"}`, string(effective.Project.Properties[0].Value))
+	assert.Equal(t, "main", effective.Branch.Name)
+	assert.Equal(t, []string{"build", "deploy"}, effective.Branch.Validations)
+	assert.Equal(t, []string{"BRONZE"}, effective.Branch.Promotions)
+	assert.Equal(t, "42", effective.Build.Name)
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"yontrack/client"
+	"yontrack/config"
+	"yontrack/utils"
+
+	"github.com/spf13/cobra"
+)
+
+// Bundled JSON schema for the CI configuration
+//
+//go:embed schemas/ci-config.json
+var ciConfigSchema []byte
+
+var ciValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validates the CI configuration file",
+	Long: `Validates the CI configuration file, without sending it to Ontrack.
+
+The file is expanded (@path includes) and rendered as a template exactly like for the
+'ci config' command, and the result is validated against a JSON schema. All the errors
+are reported with their line in the rendered configuration, including the unknown keys.
+
+	yontrack ci validate
+	yontrack ci validate --file .yontrack/ci.yaml --env-file .env --var version=1.0.0
+
+By default, the JSON schema bundled with the CLI is used. The schema can be taken
+from a local file using --schema or fetched from Ontrack using --online. In this case,
+the schema is looked for among the JSON schema definitions of Ontrack using its key
+(--schema-key, ci-config by default). The Ontrack credentials are only sent to download
+the schema when it's hosted by Ontrack.
+
+The command fails with exit code 6 if the configuration is not valid.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		schemaFile, err := cmd.Flags().GetString("schema")
+		if err != nil {
+			return err
+		}
+		online, err := cmd.Flags().GetBool("online")
+		if err != nil {
+			return err
+		}
+		schemaKey, err := cmd.Flags().GetString("schema-key")
+		if err != nil {
+			return err
+		}
+		if schemaFile != "" && online {
+			return errors.New("--schema and --online cannot be used together")
+		}
+
+		ciContext, err := getCIConfigContext(cmd)
+		if err != nil {
+			return err
+		}
+
+		schema := ciConfigSchema
+		if schemaFile != "" {
+			if schema, err = os.ReadFile(schemaFile); err != nil {
+				return fmt.Errorf("failed to read JSON schema: %w", err)
+			}
+		} else if online {
+			cfg, err := config.GetSelectedConfiguration()
+			if err != nil {
+				return err
+			}
+			if schema, err = fetchJSONSchema(cfg, schemaKey); err != nil {
+				return err
+			}
+		}
+
+		schemaErrors, err := utils.ValidateYAML(ciContext.ConfigContent, schema)
+		if err != nil {
+			return err
+		}
+		if len(schemaErrors) > 0 {
+			return schemaErrors
+		}
+		fmt.Println("The CI configuration is valid.")
+		return nil
+	},
+}
+
+// fetchJSONSchema downloads the JSON schema registered in Ontrack with the given key. The Ontrack
+// credentials are only sent when the schema is hosted by Ontrack.
+func fetchJSONSchema(cfg *config.Config, key string) ([]byte, error) {
+	var data struct {
+		JsonSchemaDefinitions []struct {
+			Id  string
+			Key string
+		}
+	}
+	if err := client.GraphQLCall(cfg, `
+		{
+			jsonSchemaDefinitions {
+				id
+				key
+			}
+		}
+	`, nil, &data); err != nil {
+		return nil, err
+	}
+	for _, definition := range data.JsonSchemaDefinitions {
+		if definition.Key == key {
+			// The ID of the schema is a URI which may be outside of Ontrack
+			ontrack := client.ForConfig(cfg)
+			var schema []byte
+			var err error
+			if ontrack.IsOntrackURL(definition.Id) {
+				schema, err = ontrack.Get(definition.Id)
+			} else {
+				schema, err = ontrack.GetAnonymous(definition.Id)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to download JSON schema %s: %w", key, err)
+			}
+			return schema, nil
+		}
+	}
+	return nil, client.NewNotFoundError("no JSON schema found in Ontrack with key %s", key)
+}
+
+func init() {
+	ciCmd.AddCommand(ciValidateCmd)
+	registerCIConfigFlags(ciValidateCmd)
+
+	ciValidateCmd.Flags().String("schema", "", "Path to a JSON schema file to use instead of the bundled one")
+	ciValidateCmd.Flags().Bool("online", false, "Downloads the JSON schema from Ontrack instead of using the bundled one")
+	ciValidateCmd.Flags().String("schema-key", "ci-config", "Key of the JSON schema definition in Ontrack, when using --online")
+}
@@ -49,6 +49,10 @@ and you can get the list of configurations using:
 
 	yontrack config list
 
+The configurations are stored in a file whose location is given by:
+
+	yontrack config path
+
 Use 'yontrack config' to see the other options.
 `,
 	// Run: func(cmd *cobra.Command, args []string) {},
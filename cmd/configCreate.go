@@ -22,6 +22,8 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 
 	config "yontrack/config"
@@ -32,6 +34,7 @@ var username string
 var password string
 var token string
 var connectionRetry config.ConnectionRetry
+var timeoutSec int
 
 // configCreateCmd represents the configCreate command
 var configCreateCmd = &cobra.Command{
@@ -45,6 +48,26 @@ using a username and a password:
 or to create a 'prod' configuration using a token:
 	
 	yontrack config create prod https://ontrack.nemerosa.net --token <token>
+
+The password and the token are stored in clear in the configuration file, unless:
+
+* they are references to the actual credential:
+
+	yontrack config create prod https://ontrack.nemerosa.net --token env:YONTRACK_TOKEN
+	yontrack config create prod https://ontrack.nemerosa.net --token file:/run/secrets/ontrack-token
+
+* --store is used to keep them in the local store, encrypted using the passphrase set by the
+  YONTRACK_CREDENTIALS_KEY environment variable:
+
+	yontrack config create prod https://ontrack.nemerosa.net --token <token> --store
+
+* they are provided by a git-credential style helper, like an OS keychain:
+
+	yontrack config create prod https://ontrack.nemerosa.net --credential-helper "git credential-osxkeychain"
+
+Using --verify, the configuration is saved only if Ontrack can be reached and accepts the credentials:
+
+	yontrack config create prod https://ontrack.nemerosa.net --token <token> --verify
 `,
 	Args: cobra.ExactValidArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -63,19 +86,70 @@ func createConfig(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	credentialHelper, err := cmd.Flags().GetString("credential-helper")
+	if err != nil {
+		return err
+	}
+	store, err := cmd.Flags().GetBool("store")
+	if err != nil {
+		return err
+	}
+	verify, err := cmd.Flags().GetBool("verify")
+	if err != nil {
+		return err
+	}
+
 	// Creates the configuration
 	var cfg = config.Config{
-		Name:            name,
-		URL:             url,
-		Username:        username,
-		Password:        password,
-		Token:           token,
-		ConnectionRetry: connectionRetry,
+		Name:             name,
+		URL:              url,
+		Username:         username,
+		Password:         password,
+		Token:            token,
+		CredentialHelper: credentialHelper,
+		ConnectionRetry:  connectionRetry,
+		TimeoutSec:       timeoutSec,
+	}
+
+	// Checks the configuration before saving it
+	if verify {
+		resolved := cfg
+		if err := resolved.ResolveCredentials(); err != nil {
+			return err
+		}
+		result, err := testConfiguration(&resolved)
+		if err != nil {
+			return fmt.Errorf("the %s configuration is not saved: %w", name, err)
+		}
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Connected to Ontrack %s as %s\n", result.Version, result.Account.Name)
+	}
+
+	// Keeps the credentials out of the configuration file, without replacing the ones
+	// of an existing configuration if it's not overridden
+	var restores []func()
+	if store {
+		if err := config.CheckConfigurationCanBeAdded(name, override); err != nil {
+			return err
+		}
+		var restore func()
+		if cfg.Password, restore, err = storeCredential(name+".password", cfg.Password); err != nil {
+			return err
+		}
+		restores = append(restores, restore)
+		if cfg.Token, restore, err = storeCredential(name+".token", cfg.Token); err != nil {
+			restores[0]()
+			return err
+		}
+		restores = append(restores, restore)
 	}
 
 	// Adds this configuration to the file
 	// and sets as default
 	if err := config.AddConfiguration(cfg, override); err != nil {
+		// The stored credentials are put back as they were
+		for _, restore := range restores {
+			restore()
+		}
 		return err
 	}
 
@@ -83,24 +157,47 @@ func createConfig(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// storeCredential saves a credential into the encrypted local store and returns the reference to use
+// in the configuration file, and a function putting back the previous value of the credential.
+// Empty values and references are returned as they are.
+func storeCredential(name string, value string) (string, func(), error) {
+	if value == "" || config.IsCredentialReference(value) {
+		return value, func() {}, nil
+	}
+	store, err := config.DefaultCredentialStore()
+	if err != nil {
+		return "", nil, err
+	}
+	previous, getErr := store.Get(name)
+	if err := store.Set(name, value); err != nil {
+		return "", nil, fmt.Errorf("cannot save the credential into the local store: %w", err)
+	}
+	restore := func() {
+		if getErr == nil {
+			_ = store.Set(name, previous)
+		} else {
+			_ = store.Delete(name)
+		}
+	}
+	return config.CredentialStorePrefix + name, restore, nil
+}
+
 func init() {
 	configCmd.AddCommand(configCreateCmd)
+	registerConfigCreateFlags(configCreateCmd)
+}
 
-	// Here you will define your flags and configuration settings.
-
-	// Cobra supports Persistent Flags which will work for this command
-	// and all subcommands, e.g.:
-	// configCreateCmd.PersistentFlags().String("foo", "", "A help for foo")
-
-	// Cobra supports local flags which will only run when this command
-	// is called directly, e.g.:
-
+func registerConfigCreateFlags(cmd *cobra.Command) {
 	// Authentication flags
 
-	configCreateCmd.Flags().StringVarP(&username, "username", "u", "", "Username for basic authentication")
-	configCreateCmd.Flags().StringVarP(&password, "password", "p", "", "Password for basic authentication")
-	configCreateCmd.Flags().StringVarP(&token, "token", "t", "", "Token based authentication (if defined, takes priority over username/password authentication)")
-	configCreateCmd.Flags().BoolP("override", "o", false, "Overrides the configuration if it already exists")
-	configCreateCmd.Flags().IntVarP(&connectionRetry.MaxWaitTimeSec, "conn-retry-wait", "", 2, "Max connection retry wait time between attempts in seconds")
-	configCreateCmd.Flags().IntVarP(&connectionRetry.MaxCount, "conn-retry-count", "", 5, "Max connection retry attempts")
+	cmd.Flags().StringVarP(&username, "username", "u", "", "Username for basic authentication")
+	cmd.Flags().StringVarP(&password, "password", "p", "", "Password for basic authentication")
+	cmd.Flags().StringVarP(&token, "token", "t", "", "Token based authentication (if defined, takes priority over username/password authentication)")
+	cmd.Flags().String("credential-helper", "", "git-credential style command providing the credentials, when neither --password nor --token is set")
+	cmd.Flags().Bool("store", false, "Saves the password or the token into the encrypted local store instead of the configuration file")
+	cmd.Flags().BoolP("override", "", false, "Overrides the configuration if it already exists")
+	cmd.Flags().Bool("verify", false, "Checks the connection to Ontrack and the credentials before saving the configuration (see 'config test')")
+	cmd.Flags().IntVarP(&connectionRetry.MaxWaitTimeSec, "conn-retry-wait", "", 2, "Max connection retry wait time between attempts in seconds")
+	cmd.Flags().IntVarP(&connectionRetry.MaxCount, "conn-retry-count", "", 5, "Max connection retry attempts")
+	cmd.Flags().IntVarP(&timeoutSec, "timeout", "", 0, "Timeout in seconds for each call to Ontrack (0 for no timeout)")
 }
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"yontrack/config"
+)
+
+// useTestConfiguration isolates the configuration file and the credential store in a temporary directory
+func useTestConfiguration(t *testing.T) *config.CredentialStore {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv(config.EnvCredentialsKey, "passphrase")
+	previous := config.ConfigFilePath
+	t.Cleanup(func() {
+		config.ConfigFilePath = previous
+	})
+	config.ConfigFilePath = filepath.Join(dir, "config.yaml")
+	store, err := config.DefaultCredentialStore()
+	require.NoError(t, err)
+	return store
+}
+
+func TestCreateConfig_StoreExisting(t *testing.T) {
+	store := useTestConfiguration(t)
+	create := func(args ...string) error {
+		return createConfig(newTestCommand(t, registerConfigCreateFlags, args...), []string{"prod", "https://ontrack.example.com"})
+	}
+
+	require.NoError(t, create("--token", "old", "--store"))
+
+	// The credential of the existing configuration is kept
+	err := create("--token", "new", "--store")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+	value, err := store.Get("prod.token")
+	require.NoError(t, err)
+	assert.Equal(t, "old", value)
+
+	require.NoError(t, create("--token", "new", "--store", "--override"))
+	value, err = store.Get("prod.token")
+	require.NoError(t, err)
+	assert.Equal(t, "new", value)
+}
+
+func TestStoreCredential_Restore(t *testing.T) {
+	store := useTestConfiguration(t)
+	require.NoError(t, store.Set("prod.token", "old"))
+
+	reference, restore, err := storeCredential("prod.token", "new")
+	require.NoError(t, err)
+	assert.Equal(t, config.CredentialStorePrefix+"prod.token", reference)
+	restore()
+	value, err := store.Get("prod.token")
+	require.NoError(t, err)
+	assert.Equal(t, "old", value)
+
+	// A credential which did not exist is removed
+	_, restore, err = storeCredential("test.token", "new")
+	require.NoError(t, err)
+	restore()
+	_, err = store.Get("test.token")
+	assert.Error(t, err)
+}
@@ -23,40 +23,80 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"strconv"
 
 	"github.com/spf13/cobra"
 
 	config "yontrack/config"
+	"yontrack/output"
 )
 
 // configListCmd represents the configList command
 var configListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all configurations",
-	Long:  `Displays the list of all existing configurations.`,
+	Long: `Displays the list of all existing configurations.
+
+The credentials are never displayed, whatever the output format.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		printer, err := output.FromCommand(cmd)
+		if err != nil {
+			return err
+		}
 		root, err := config.ReadRootConfiguration()
 		if err != nil {
 			return err
 		}
+		items := make([]configListItem, 0, len(root.Configurations))
+		rows := make([][]string, 0, len(root.Configurations))
 		for _, item := range root.Configurations {
-			var line string
-			if item.Name == root.Selected {
-				line += "* "
-			} else {
-				line += "  "
+			listItem := configListItem{
+				Name:     item.Name,
+				URL:      item.URL,
+				Selected: item.Name == root.Selected,
+				Disabled: item.Disabled,
 			}
-			line += item.Name
-			if item.Disabled {
-				line += " (disabled)"
+			items = append(items, listItem)
+			selected := ""
+			if listItem.Selected {
+				selected = "*"
 			}
-			fmt.Println(line)
-			fmt.Printf("  %s\n", item.URL)
+			rows = append(rows, []string{selected, item.Name, item.URL, strconv.FormatBool(item.Disabled)})
 		}
-		return nil
+		return printer.Print(&output.Result{
+			Value:   items,
+			Headers: []string{"SELECTED", "NAME", "URL", "DISABLED"},
+			Rows:    rows,
+			Plain: func(w io.Writer) error {
+				for _, item := range items {
+					var line string
+					if item.Selected {
+						line += "* "
+					} else {
+						line += "  "
+					}
+					line += item.Name
+					if item.Disabled {
+						line += " (disabled)"
+					}
+					_, _ = fmt.Fprintln(w, line)
+					_, _ = fmt.Fprintf(w, "  %s\n", item.URL)
+				}
+				return nil
+			},
+		})
 	},
 }
 
+// configListItem is the public view of a configuration, without its credentials
+type configListItem struct {
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+	Selected bool   `json:"selected"`
+	Disabled bool   `json:"disabled"`
+}
+
 func init() {
 	configCmd.AddCommand(configListCmd)
 
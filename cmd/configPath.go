@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"yontrack/config"
+	"yontrack/output"
+)
+
+// configPathCmd displays the path to the configuration file in effect
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Displays the path to the configuration file",
+	Long: `Displays the path to the configuration file in effect, which is, in order:
+
+* the file set by the --config flag (source: flag)
+* the file set by the YONTRACK_CONFIG environment variable (source: env)
+* the first .yontrack-config.yaml file found in the current directory or its parents (source: project)
+* $XDG_CONFIG_HOME/yontrack/config.yaml, $XDG_CONFIG_HOME defaulting to ~/.config (source: user)
+
+For example:
+
+	yontrack config path
+	yontrack config path --output json
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		printer, err := output.FromCommand(cmd)
+		if err != nil {
+			return err
+		}
+		path, source, err := config.ResolveConfigFilePath()
+		if err != nil {
+			return err
+		}
+		exists := false
+		if _, err := os.Stat(path); err == nil {
+			exists = true
+		}
+		return printer.Print(&output.Result{
+			Value: configPathItem{
+				Path:   path,
+				Source: source,
+				Exists: exists,
+			},
+			Headers: []string{"PATH", "SOURCE", "EXISTS"},
+			Rows:    [][]string{{path, source, strconv.FormatBool(exists)}},
+			Plain: func(w io.Writer) error {
+				_, err := fmt.Fprintln(w, path)
+				return err
+			},
+		})
+	},
+}
+
+// configPathItem describes the configuration file in effect
+type configPathItem struct {
+	Path   string `json:"path"`
+	Source string `json:"source"`
+	Exists bool   `json:"exists"`
+}
+
+func init() {
+	configCmd.AddCommand(configPathCmd)
+}
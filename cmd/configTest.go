@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"yontrack/client"
+	"yontrack/config"
+	"yontrack/output"
+)
+
+// configTestCmd checks the connection to Ontrack using a configuration
+var configTestCmd = &cobra.Command{
+	Use:     "test [NAME]",
+	Aliases: []string{"login"},
+	Short:   "Checks the connection to Ontrack",
+	Long: `Checks that Ontrack can be reached and that the credentials of a configuration are accepted,
+and displays the authenticated account, the version of Ontrack and the global roles of the account.
+
+To test the configuration in effect:
+
+	yontrack config test
+
+To test another configuration, without selecting it:
+
+	yontrack config test prod
+
+The exit code tells which check failed: 8 when Ontrack cannot be reached, 3 when the credentials
+are not accepted, 2 when the configuration cannot be found.
+`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		printer, err := output.FromCommand(cmd)
+		if err != nil {
+			return err
+		}
+		var cfg *config.Config
+		if len(args) > 0 {
+			cfg, err = config.GetConfiguration(args[0])
+		} else {
+			cfg, err = config.GetSelectedConfiguration()
+		}
+		if err != nil {
+			return err
+		}
+		result, err := testConfiguration(cfg)
+		if err != nil {
+			return err
+		}
+		roles := strings.Join(result.Account.GlobalRoles, ",")
+		return printer.Print(&output.Result{
+			Value:   result,
+			Headers: []string{"NAME", "URL", "VERSION", "ACCOUNT", "GLOBAL ROLES", "DISABLED"},
+			Rows:    [][]string{{result.Name, result.URL, result.Version, result.Account.Name, roles, strconv.FormatBool(result.Disabled)}},
+			Plain: func(w io.Writer) error {
+				_, _ = fmt.Fprintf(w, "Configuration %s", result.Name)
+				if result.Disabled {
+					_, _ = fmt.Fprint(w, " (disabled)")
+				}
+				_, _ = fmt.Fprintln(w)
+				_, _ = fmt.Fprintf(w, "Ontrack URL %s\n", result.URL)
+				_, _ = fmt.Fprintf(w, "Ontrack Version %s\n", result.Version)
+				if result.Account.FullName != "" {
+					_, _ = fmt.Fprintf(w, "Account %s (%s)\n", result.Account.Name, result.Account.FullName)
+				} else {
+					_, _ = fmt.Fprintf(w, "Account %s\n", result.Account.Name)
+				}
+				if roles == "" {
+					roles = "none"
+				}
+				_, _ = fmt.Fprintf(w, "Global roles %s\n", roles)
+				return nil
+			},
+		})
+	},
+}
+
+// configTestResult is the outcome of a successful test of a configuration
+type configTestResult struct {
+	Name     string         `json:"name"`
+	URL      string         `json:"url"`
+	Disabled bool           `json:"disabled"`
+	Version  string         `json:"version"`
+	Account  client.Account `json:"account"`
+}
+
+// testConfiguration checks that Ontrack can be reached and that it accepts the credentials of the
+// configuration, whose credential references must have been resolved. Disabled configurations are
+// tested as well.
+func testConfiguration(cfg *config.Config) (*configTestResult, error) {
+	enabled := *cfg
+	enabled.Disabled = false
+
+	if err := client.ForConfig(&enabled).Ping(); err != nil {
+		return nil, fmt.Errorf("cannot reach Ontrack at %s: %w", cfg.URL, err)
+	}
+	version, err := client.GetVersion(&enabled)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get the version of Ontrack at %s: %w", cfg.URL, err)
+	}
+	account, err := client.GetAccount(&enabled)
+	if err != nil {
+		return nil, fmt.Errorf("authentication failed for the %s configuration: %w", cfg.Name, err)
+	}
+	return &configTestResult{
+		Name:     cfg.Name,
+		URL:      cfg.URL,
+		Disabled: cfg.Disabled,
+		Version:  version,
+		Account:  *account,
+	}, nil
+}
+
+func init() {
+	configCmd.AddCommand(configTestCmd)
+}
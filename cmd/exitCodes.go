@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"yontrack/client"
+	"yontrack/config"
+	"yontrack/utils"
+)
+
+// Exit codes returned by the CLI, so that scripts can react to the different
+// categories of failures
+const (
+	// ExitOK is returned when the command succeeds
+	ExitOK = 0
+	// ExitError is returned for any failure not covered by the other codes
+	ExitError = 1
+	// ExitConfig is returned when the CLI configuration is missing or invalid
+	ExitConfig = 2
+	// ExitAuth is returned when the authentication fails or when the user is not allowed
+	ExitAuth = 3
+	// ExitNotFound is returned when an entity (project, branch, build...) cannot be found
+	ExitNotFound = 4
+	// ExitServer is returned when Ontrack fails with a server error
+	ExitServer = 5
+	// ExitValidation is returned when the input is rejected by Ontrack
+	ExitValidation = 6
+	// ExitTimeout is returned when Ontrack does not answer in time
+	ExitTimeout = 7
+	// ExitNetwork is returned when Ontrack cannot be reached at all
+	ExitNetwork = 8
+	// ExitGate is returned when a build does not satisfy the conditions of a gate
+	ExitGate = 9
+)
+
+// gateError is returned when a build does not satisfy the conditions checked or
+// waited for by a command
+type gateError struct {
+	Message string
+	// The conditions were not met in time
+	Timeout bool
+}
+
+func (e *gateError) Error() string {
+	return e.Message
+}
+
+// ExitCode returns the exit code to use for a given error
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var gate *gateError
+	if errors.As(err, &gate) {
+		if gate.Timeout {
+			return ExitTimeout
+		}
+		return ExitGate
+	}
+
+	var configurationError *config.ConfigurationError
+	if errors.As(err, &configurationError) {
+		return ExitConfig
+	}
+
+	var httpError *client.HTTPError
+	if errors.As(err, &httpError) {
+		switch {
+		case httpError.StatusCode == http.StatusUnauthorized || httpError.StatusCode == http.StatusForbidden:
+			return ExitAuth
+		case httpError.StatusCode == http.StatusRequestTimeout || httpError.StatusCode == http.StatusGatewayTimeout:
+			return ExitTimeout
+		case httpError.StatusCode >= 500:
+			return ExitServer
+		default:
+			return ExitError
+		}
+	}
+
+	if client.IsAuthorization(err) {
+		return ExitAuth
+	}
+	if client.IsNotFound(err) {
+		return ExitNotFound
+	}
+	if client.IsValidation(err) || client.IsAlreadyExists(err) {
+		return ExitValidation
+	}
+	var payloadErrors client.PayloadErrors
+	if errors.As(err, &payloadErrors) {
+		return ExitValidation
+	}
+	var schemaErrors utils.SchemaErrors
+	if errors.As(err, &schemaErrors) {
+		return ExitValidation
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ExitTimeout
+	}
+	// Only the errors of network operations are considered, since the errors of the
+	// local files (fs.PathError) wrap a syscall.Errno which also implements net.Error
+	var urlError *url.Error
+	var opError *net.OpError
+	if errors.As(err, &urlError) {
+		if urlError.Timeout() {
+			return ExitTimeout
+		}
+		return ExitNetwork
+	}
+	if errors.As(err, &opError) {
+		if opError.Timeout() {
+			return ExitTimeout
+		}
+		return ExitNetwork
+	}
+
+	return ExitError
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"yontrack/client"
+	"yontrack/config"
+	"yontrack/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"no error", nil, ExitOK},
+		{"generic", errors.New("boom"), ExitError},
+		{"configuration", &config.ConfigurationError{Message: "No current configuration"}, ExitConfig},
+		{"unauthorized", &client.HTTPError{StatusCode: 401}, ExitAuth},
+		{"forbidden", &client.HTTPError{StatusCode: 403}, ExitAuth},
+		{"gateway timeout", &client.HTTPError{StatusCode: 504}, ExitTimeout},
+		{"server error", &client.HTTPError{StatusCode: 500}, ExitServer},
+		{"bad request", &client.HTTPError{StatusCode: 400}, ExitError},
+		{"not found in CLI", client.NewNotFoundError("no build found"), ExitNotFound},
+		{"not found in GraphQL", client.GraphQLErrors{
+			{Message: "Not found", Extensions: map[string]interface{}{"exception": "BuildNotFoundException"}},
+		}, ExitNotFound},
+		{"access denied in payload", client.PayloadErrors{
+			{Message: "Denied", Exception: "org.springframework.security.access.AccessDeniedException"},
+		}, ExitAuth},
+		{"anonymous", fmt.Errorf("authentication failed: %w", &client.AuthenticationError{Message: "anonymous"}), ExitAuth},
+		{"payload error", client.PayloadErrors{{Message: "Rejected"}}, ExitValidation},
+		{"already exists", fmt.Errorf("source build: %w", client.PayloadErrors{
+			{Message: "Exists", Exception: "ProjectNameAlreadyDefinedException"},
+		}), ExitValidation},
+		{"schema", utils.SchemaErrors{{Line: 3, Message: "unknown key brnach"}}, ExitValidation},
+		{"deadline", fmt.Errorf("call: %w", context.DeadlineExceeded), ExitTimeout},
+		{"gate", &gateError{Message: "promotion GOLD not granted"}, ExitGate},
+		{"gate timeout", &gateError{Message: "promotion GOLD not granted in time", Timeout: true}, ExitTimeout},
+		{"network", &url.Error{Op: "Post", URL: "http://localhost", Err: errors.New("connection refused")}, ExitNetwork},
+		{"network operation", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, ExitNetwork},
+		{"network timeout", &url.Error{Op: "Post", URL: "http://localhost", Err: context.DeadlineExceeded}, ExitTimeout},
+		{"local file", fmt.Errorf("include: %w", readMissingFile()), ExitError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ExitCode(tt.err))
+		})
+	}
+}
+
+func readMissingFile() error {
+	_, err := os.ReadFile(filepath.Join(os.TempDir(), "yontrack-missing", "ci.yaml"))
+	return err
+}
@@ -0,0 +1,16 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestCommand returns a command having the flags registered by register, parsed from args
+func newTestCommand(t *testing.T, register func(cmd *cobra.Command), args ...string) *cobra.Command {
+	cmd := &cobra.Command{Use: "test"}
+	register(cmd)
+	require.NoError(t, cmd.ParseFlags(args))
+	return cmd
+}
@@ -0,0 +1,22 @@
+package cmd
+
+import "yontrack/output"
+
+// branchEnvVariables returns the variables identifying a branch for the env output
+func branchEnvVariables(projectID string, projectName string, branchID string, branchName string) []output.Variable {
+	return []output.Variable{
+		{Name: "YONTRACK_PROJECT_ID", Value: projectID},
+		{Name: "YONTRACK_PROJECT_NAME", Value: projectName},
+		{Name: "YONTRACK_BRANCH_ID", Value: branchID},
+		{Name: "YONTRACK_BRANCH_NAME", Value: branchName},
+	}
+}
+
+// buildEnvVariables returns the variables identifying a build for the env output
+func buildEnvVariables(projectID string, projectName string, branchID string, branchName string, buildID string, buildName string) []output.Variable {
+	return append(
+		branchEnvVariables(projectID, projectName, branchID, branchName),
+		output.Variable{Name: "YONTRACK_BUILD_ID", Value: buildID},
+		output.Variable{Name: "YONTRACK_BUILD_NAME", Value: buildName},
+	)
+}
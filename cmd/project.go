@@ -40,6 +40,17 @@ You can create a new project using:
 with an optional description:
 
 	yontrack project create NAME --description "..."
+
+Other commands are available to get, update, disable, enable or delete a project:
+
+	yontrack project get NAME
+	yontrack project update NAME --description "..."
+	yontrack project disable NAME
+	yontrack project enable NAME
+	yontrack project delete NAME
+
+For all these commands, the --project flag or the YONTRACK_PROJECT_NAME
+environment variable can be used instead of NAME.
 `,
 	// Run: func(cmd *cobra.Command, args []string) {},
 }
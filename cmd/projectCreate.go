@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"yontrack/client"
+	"yontrack/config"
+	"yontrack/output"
+
+	"github.com/spf13/cobra"
+)
+
+var projectCreateCmd = &cobra.Command{
+	Use:   "create [NAME]",
+	Short: "Creates a project",
+	Long: `Creates a project.
+
+The name of the project is given as an argument, or by the --project flag,
+defaulting to the YONTRACK_PROJECT_NAME environment variable.
+
+	yontrack project create my-project
+	yontrack project create my-project --description "My project"
+	yontrack project create my-project --disabled
+
+The command fails if the project already exists.
+`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		printer, err := output.FromCommand(cmd)
+		if err != nil {
+			return err
+		}
+		name, err := getProjectName(cmd, args)
+		if err != nil {
+			return err
+		}
+		description, err := cmd.Flags().GetString("description")
+		if err != nil {
+			return err
+		}
+		disabled, err := cmd.Flags().GetBool("disabled")
+		if err != nil {
+			return err
+		}
+		cfg, err := config.GetSelectedConfiguration()
+		if err != nil {
+			return err
+		}
+		project, err := client.CreateProject(cfg, name, description, disabled)
+		if err != nil {
+			return err
+		}
+		return printProject(printer, project, nil)
+	},
+}
+
+func init() {
+	projectCmd.AddCommand(projectCreateCmd)
+
+	projectCreateCmd.Flags().StringP("project", "p", "", "Name of the project")
+	projectCreateCmd.Flags().StringP("description", "d", "", "Description of the project")
+	projectCreateCmd.Flags().Bool("disabled", false, "Creates the project in a disabled state")
+}
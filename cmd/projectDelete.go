@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"yontrack/client"
+	"yontrack/utils"
+
+	"github.com/spf13/cobra"
+)
+
+var projectDeleteCmd = &cobra.Command{
+	Use:   "delete [NAME]",
+	Short: "Deletes a project",
+	Long: `Deletes a project, with all its branches and builds.
+
+The project is identified by its NAME, or by the --project flag,
+defaulting to the YONTRACK_PROJECT_NAME environment variable.
+
+A confirmation is asked before the deletion, unless --yes is used:
+
+	yontrack project delete my-project
+	yontrack project delete --project my-project --yes
+`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		yes, err := cmd.Flags().GetBool("yes")
+		if err != nil {
+			return err
+		}
+		cfg, project, id, err := loadProject(cmd, args)
+		if err != nil {
+			return err
+		}
+		if !yes {
+			ok, err := utils.Confirm(os.Stdin, os.Stderr, fmt.Sprintf("Deleting project %s and all its content. Continue?", project.Name))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return fmt.Errorf("deletion of project %s cancelled", project.Name)
+			}
+		}
+		return client.DeleteProject(cfg, id)
+	},
+}
+
+func init() {
+	projectCmd.AddCommand(projectDeleteCmd)
+
+	projectDeleteCmd.Flags().StringP("project", "p", "", "Name of the project")
+	projectDeleteCmd.Flags().BoolP("yes", "y", false, "Does not ask for confirmation")
+}
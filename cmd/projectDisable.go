@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"yontrack/client"
+
+	"github.com/spf13/cobra"
+)
+
+var projectDisableCmd = &cobra.Command{
+	Use:   "disable [NAME]",
+	Short: "Disables a project",
+	Long: `Disables a project.
+
+The project is identified by its NAME, or by the --project flag,
+defaulting to the YONTRACK_PROJECT_NAME environment variable.
+
+	yontrack project disable my-project
+`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, _, id, err := loadProject(cmd, args)
+		if err != nil {
+			return err
+		}
+		return client.SetProjectDisabled(cfg, id, true)
+	},
+}
+
+func init() {
+	projectCmd.AddCommand(projectDisableCmd)
+
+	projectDisableCmd.Flags().StringP("project", "p", "", "Name of the project")
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"yontrack/client"
+
+	"github.com/spf13/cobra"
+)
+
+var projectEnableCmd = &cobra.Command{
+	Use:   "enable [NAME]",
+	Short: "Enables a project",
+	Long: `Enables a project.
+
+The project is identified by its NAME, or by the --project flag,
+defaulting to the YONTRACK_PROJECT_NAME environment variable.
+
+	yontrack project enable my-project
+`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, _, id, err := loadProject(cmd, args)
+		if err != nil {
+			return err
+		}
+		return client.SetProjectDisabled(cfg, id, false)
+	},
+}
+
+func init() {
+	projectCmd.AddCommand(projectEnableCmd)
+
+	projectEnableCmd.Flags().StringP("project", "p", "", "Name of the project")
+}
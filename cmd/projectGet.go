@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"yontrack/client"
+	"yontrack/config"
+	"yontrack/output"
+	"yontrack/utils"
+
+	"github.com/spf13/cobra"
+)
+
+var projectGetCmd = &cobra.Command{
+	Use:   "get [NAME]",
+	Short: "Displays a project",
+	Long: `Displays a project.
+
+The project is identified by its NAME, or by the --project flag,
+defaulting to the YONTRACK_PROJECT_NAME environment variable.
+
+	yontrack project get my-project
+	yontrack project get --project my-project --output json
+`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		printer, err := output.FromCommand(cmd)
+		if err != nil {
+			return err
+		}
+		_, project, _, err := loadProject(cmd, args)
+		if err != nil {
+			return err
+		}
+		return printProject(printer, project, func(w io.Writer) error {
+			_, _ = fmt.Fprintf(w, "ID:          %s\n", project.ID)
+			_, _ = fmt.Fprintf(w, "Name:        %s\n", project.Name)
+			_, _ = fmt.Fprintf(w, "Description: %s\n", project.Description)
+			_, _ = fmt.Fprintf(w, "Disabled:    %t\n", project.Disabled)
+			_, _ = fmt.Fprintf(w, "Created:     %s by %s\n", project.Creation.Time, project.Creation.User)
+			return nil
+		})
+	},
+}
+
+// getProjectName gets the name of the project from the first argument if any,
+// or from the --project flag
+func getProjectName(cmd *cobra.Command, args []string) (string, error) {
+	if len(args) > 0 && args[0] != "" {
+		return args[0], nil
+	}
+	return utils.GetProjectFlag(cmd)
+}
+
+// printProject prints a project using the selected output format
+func printProject(printer *output.Printer, project *client.Project, plain func(w io.Writer) error) error {
+	return printer.Print(&output.Result{
+		Value:   project,
+		Headers: []string{"ID", "NAME", "DESCRIPTION", "DISABLED"},
+		Rows:    [][]string{{project.ID, project.Name, project.Description, fmt.Sprint(project.Disabled)}},
+		Env: []output.Variable{
+			{Name: "YONTRACK_PROJECT_ID", Value: project.ID},
+			{Name: "YONTRACK_PROJECT_NAME", Value: project.Name},
+		},
+		Plain: plain,
+	})
+}
+
+// loadProject loads the project identified by the arguments or the flags,
+// returning the configuration used to load it and its numeric ID
+func loadProject(cmd *cobra.Command, args []string) (*config.Config, *client.Project, int, error) {
+	name, err := getProjectName(cmd, args)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	cfg, err := config.GetSelectedConfiguration()
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	project, err := client.GetProjectByName(cfg, name)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	id, err := project.IntID()
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return cfg, project, id, nil
+}
+
+func init() {
+	projectCmd.AddCommand(projectGetCmd)
+
+	projectGetCmd.Flags().StringP("project", "p", "", "Name of the project")
+}
@@ -23,8 +23,10 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	client "yontrack/client"
 	config "yontrack/config"
+	"yontrack/output"
 
 	"github.com/spf13/cobra"
 )
@@ -42,13 +44,24 @@ var projectListCmd = &cobra.Command{
 By default, only the names are displayed. You can display the ID instead:
 
 	yontrack project list --show-id
+
+or use any other output format:
+
+	yontrack project list --output table
+	yontrack project list --output json
+	yontrack project list --template '{{range .}}{{.name}}{{"\n"}}{{end}}'
 `,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return projectList()
+		return projectList(cmd)
 	},
 }
 
-func projectList() error {
+func projectList(cmd *cobra.Command) error {
+	printer, err := output.FromCommand(cmd)
+	if err != nil {
+		return err
+	}
+
 	config, err := config.GetSelectedConfiguration()
 	if err != nil {
 		return err
@@ -59,15 +72,26 @@ func projectList() error {
 		return err
 	}
 
+	rows := make([][]string, 0, len(data.Projects))
 	for _, project := range data.Projects {
-		if showID {
-			fmt.Println(project.ID)
-		} else {
-			fmt.Println(project.Name)
-		}
+		rows = append(rows, []string{fmt.Sprint(project.ID), project.Name})
 	}
 
-	return nil
+	return printer.Print(&output.Result{
+		Value:   data.Projects,
+		Headers: []string{"ID", "NAME"},
+		Rows:    rows,
+		Plain: func(w io.Writer) error {
+			for _, project := range data.Projects {
+				if showID {
+					_, _ = fmt.Fprintln(w, project.ID)
+				} else {
+					_, _ = fmt.Fprintln(w, project.Name)
+				}
+			}
+			return nil
+		},
+	})
 }
 
 func init() {
@@ -89,6 +113,6 @@ type projectListResponse struct {
 }
 
 type project struct {
-	ID   int
-	Name string
+	ID   int    `json:"id"`
+	Name string `json:"name"`
 }
@@ -63,9 +63,7 @@ The '--auto-create' option can be used to disable this behaviour altogether:
 		// Data
 		var data struct {
 			SetProjectAutoPromotionLevelProperty struct {
-				Errors []struct {
-					Message string
-				}
+				Errors []client.GraphQLError
 			}
 		}
 
@@ -81,6 +79,8 @@ The '--auto-create' option can be used to disable this behaviour altogether:
 			}) {
 				errors {
 					message
+					exception
+					location
 				}
 			}
 		}
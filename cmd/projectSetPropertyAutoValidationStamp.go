@@ -72,9 +72,7 @@ The '--auto-create' option can be used to disable this behaviour altogether:
 		// Data
 		var data struct {
 			SetProjectAutoValidationStampProperty struct {
-				Errors []struct {
-					Message string
-				}
+				Errors []client.GraphQLError
 			}
 		}
 
@@ -92,6 +90,8 @@ The '--auto-create' option can be used to disable this behaviour altogether:
 				}) {
 					errors {
 						message
+						exception
+						location
 					}
 				}
 			}
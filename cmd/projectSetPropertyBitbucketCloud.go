@@ -72,9 +72,7 @@ Example:
 
 		var data struct {
 			SetProjectBitbucketCloudConfigurationProperty struct {
-				Errors []struct {
-					Message string
-				}
+				Errors []client.GraphQLError
 			}
 		}
 		if err := client.GraphQLCall(cfg, `
@@ -94,6 +92,8 @@ Example:
 				}) {
 					errors {
 						message
+						exception
+						location
 					}
 				}
 			}
@@ -71,9 +71,7 @@ Example:
 
 		var data struct {
 			SetProjectGitHubConfigurationProperty struct {
-				Errors []struct {
-					Message string
-				}
+				Errors []client.GraphQLError
 			}
 		}
 		if err := client.GraphQLCall(cfg, `
@@ -93,6 +91,8 @@ Example:
 				}) {
 					errors {
 						message
+						exception
+						location
 					}
 				}
 			}
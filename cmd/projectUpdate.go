@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"errors"
+	"yontrack/client"
+	"yontrack/output"
+
+	"github.com/spf13/cobra"
+)
+
+var projectUpdateCmd = &cobra.Command{
+	Use:   "update [NAME]",
+	Short: "Updates a project",
+	Long: `Updates the name and/or the description of a project.
+
+The project is identified by its NAME, or by the --project flag,
+defaulting to the YONTRACK_PROJECT_NAME environment variable.
+
+	yontrack project update my-project --description "New description"
+	yontrack project update my-project --name my-new-project
+
+Only the given values are updated.
+`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		printer, err := output.FromCommand(cmd)
+		if err != nil {
+			return err
+		}
+
+		var name, description *string
+		if cmd.Flags().Changed("name") {
+			value, err := cmd.Flags().GetString("name")
+			if err != nil {
+				return err
+			}
+			name = &value
+		}
+		if cmd.Flags().Changed("description") {
+			value, err := cmd.Flags().GetString("description")
+			if err != nil {
+				return err
+			}
+			description = &value
+		}
+		if name == nil && description == nil {
+			return errors.New("nothing to update (use --name and/or --description)")
+		}
+
+		cfg, _, id, err := loadProject(cmd, args)
+		if err != nil {
+			return err
+		}
+		project, err := client.UpdateProject(cfg, id, name, description)
+		if err != nil {
+			return err
+		}
+		return printProject(printer, project, nil)
+	},
+}
+
+func init() {
+	projectCmd.AddCommand(projectUpdateCmd)
+
+	projectUpdateCmd.Flags().StringP("project", "p", "", "Name of the project")
+	projectUpdateCmd.Flags().StringP("name", "n", "", "New name of the project")
+	projectUpdateCmd.Flags().StringP("description", "d", "", "New description of the project")
+}
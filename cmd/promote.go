@@ -87,9 +87,7 @@ var promoteCmd = &cobra.Command{
 		// Data
 		var data struct {
 			CreatePromotionRun struct {
-				Errors []struct {
-					Message string
-				}
+				Errors []client.GraphQLError
 			}
 		}
 
@@ -113,6 +111,8 @@ var promoteCmd = &cobra.Command{
 				}) {
 					errors {
 						message
+						exception
+						location
 					}
 				}
 			}
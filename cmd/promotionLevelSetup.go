@@ -117,7 +117,7 @@ func init() {
 	promotionLevelSetupCmd.Flags().StringP("description", "d", "", "Description of the promotion level")
 
 	promotionLevelSetupCmd.Flags().StringSliceP("validation", "v", []string{}, "Validations the promotion level needs")
-	promotionLevelSetupCmd.Flags().StringSliceP("depends-on", "o", []string{}, "Promotions the promotion level needs")
+	promotionLevelSetupCmd.Flags().StringSliceP("depends-on", "", []string{}, "Promotions the promotion level needs")
 	promotionLevelSetupCmd.Flags().StringP("include", "i", "", "Including validation stamps using a regular expression")
 	promotionLevelSetupCmd.Flags().StringP("exclude", "x", "", "Excluding validation stamps using a regular expression")
 
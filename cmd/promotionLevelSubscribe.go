@@ -30,7 +30,10 @@ func init() {
 
 	promotionLevelSubscribeCmd.PersistentFlags().StringP("promotion", "l", "", "Name of the promotion level")
 	promotionLevelSubscribeCmd.PersistentFlags().StringP("name", "n", "", "Name of the subscription")
-	promotionLevelSubscribeCmd.PersistentFlags().StringP("template", "", "", "Custom template for the notification")
+	promotionLevelSubscribeCmd.PersistentFlags().String("notification-template", "", "Custom template for the notification")
+	// Former name of --notification-template, which clashed with the global --template output flag
+	promotionLevelSubscribeCmd.PersistentFlags().String("template", "", "Custom template for the notification")
+	_ = promotionLevelSubscribeCmd.PersistentFlags().MarkDeprecated("template", "use --notification-template instead")
 
 	err := promotionLevelSubscribeCmd.MarkPersistentFlagRequired("promotion")
 	if err != nil {
@@ -41,3 +44,12 @@ func init() {
 		return
 	}
 }
+
+// getNotificationTemplate returns the custom template for the notification, set by --notification-template
+// or by its deprecated --template name
+func getNotificationTemplate(cmd *cobra.Command) (string, error) {
+	if cmd.Flags().Changed("template") && !cmd.Flags().Changed("notification-template") {
+		return cmd.Flags().GetString("template")
+	}
+	return cmd.Flags().GetString("notification-template")
+}
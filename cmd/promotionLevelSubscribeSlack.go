@@ -37,7 +37,7 @@ By default, the subscription listens to "new_promotion_run" events.
 			return err
 		}
 
-		template, err := cmd.Flags().GetString("template")
+		template, err := getNotificationTemplate(cmd)
 		if err != nil {
 			return err
 		}
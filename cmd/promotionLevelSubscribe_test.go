@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetNotificationTemplate(t *testing.T) {
+	register := func(cmd *cobra.Command) {
+		cmd.Flags().AddFlagSet(promotionLevelSubscribeCmd.PersistentFlags())
+	}
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{nil, ""},
+		{[]string{"--notification-template", "Build ${build}"}, "Build ${build}"},
+		{[]string{"--template", "Build ${build}"}, "Build ${build}"},
+		{[]string{"--template", "old", "--notification-template", "new"}, "new"},
+	}
+	for _, test := range tests {
+		template, err := getNotificationTemplate(newTestCommand(t, register, test.args...))
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, template, test.args)
+	}
+}
@@ -20,6 +20,8 @@ func SetProperty(entityType string, entityNames map[string]string, typeName stri
 			set{{.EntityTypeName}}Property(input: { {{.EntityInput}}, property: $propertyName, value: $propertyValue }) {
 				errors {
 					message
+					exception
+					location
 				}
 			}
 		}
@@ -84,9 +86,7 @@ var PropertyMapping = map[string]map[string]string{
 }
 
 type setPropertyPayload struct {
-	Errors []struct {
-		Message string
-	}
+	Errors []client.GraphQLError
 }
 
 type setPropertyQueryTmplInput struct {
@@ -22,7 +22,10 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"os"
 	"yontrack/config"
+	"yontrack/output"
+	"yontrack/utils"
 
 	"github.com/spf13/cobra"
 )
@@ -45,6 +48,14 @@ Note that you can create several configurations and manage them using
 
 	yontrack config
 	
+and pick one for a single command using the --context NAME flag.
+
+Without any configuration file, for example in ephemeral CI containers, the connection
+can be defined using the YONTRACK_URL, YONTRACK_TOKEN (or YONTRACK_USERNAME and YONTRACK_PASSWORD),
+YONTRACK_CONN_RETRY_COUNT, YONTRACK_CONN_RETRY_WAIT and YONTRACK_TIMEOUT environment variables.
+When YONTRACK_URL is set, these variables take priority over the selected configuration,
+but not over the --context flag.
+	
 Examples of usages:
 
 * To get the list of projects:
@@ -66,16 +77,35 @@ Examples of usages:
 * To create a validation run on an existing build:
 
 	yontrack build validate --project my-project --branch release/1.0 --build 123 --validation TESTS --status PASSED
+
+The exit code of the CLI depends on the category of failure:
+
+	0 - success
+	1 - any other error
+	2 - configuration error (missing, invalid or unknown configuration)
+	3 - authentication or authorization error
+	4 - entity not found (project, branch, build...)
+	5 - server error
+	6 - input rejected by Ontrack (validation error, already existing entity...)
+	7 - timeout
+	8 - network error (Ontrack cannot be reached)
+	9 - build gate not satisfied (build wait, build check)
 	`,
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	// Run: func(cmd *cobra.Command, args []string) {},
 }
 
+// Patterns identifying the secret variables
+var secretPatterns []string
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// The error, if any, has already been printed by Cobra and is turned into an exit code.
 func Execute() {
-	cobra.CheckErr(rootCmd.Execute())
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(ExitCode(err))
+	}
 }
 
 func init() {
@@ -83,7 +113,16 @@ func init() {
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 
-	rootCmd.PersistentFlags().StringVar(&config.ConfigFilePath, "config", "./.yontrack-config.yaml", "Configuration file path.")
+	rootCmd.PersistentFlags().StringVar(&config.ConfigFilePath, "config", "", "Configuration file path. Defaults to $YONTRACK_CONFIG, then to the first .yontrack-config.yaml file found in the current directory or its parents, then to $XDG_CONFIG_HOME/yontrack/config.yaml (see 'config path').")
+
+	rootCmd.PersistentFlags().StringVar(&config.ContextName, "context", "", "Name of the configuration to use for this command, instead of the selected one.")
 
 	rootCmd.PersistentFlags().BoolVar(&config.GraphQLLogging, "graphql-log", false, "Enable traces on the GraphQL calls.")
+
+	rootCmd.PersistentFlags().StringSliceVar(&secretPatterns, "secret-patterns", utils.DefaultSecretPatterns, "Variables whose name contains one of these patterns have their value masked in the diagnostics and logs.")
+	cobra.OnInitialize(func() {
+		utils.Secrets.SetPatterns(secretPatterns)
+	})
+
+	output.RegisterPersistentFlags(rootCmd)
 }
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRootCmd_GlobalOutputFlags(t *testing.T) {
+	// Merging the flags panics when a command redefines the shorthand of a global flag
+	var check func(cmd *cobra.Command)
+	check = func(cmd *cobra.Command) {
+		cmd.InheritedFlags()
+		for _, name := range []string{"output", "template"} {
+			flag := cmd.Flags().Lookup(name)
+			if assert.NotNil(t, flag, cmd.CommandPath()) && cmd != promotionLevelSubscribeCmd && cmd.Parent() != promotionLevelSubscribeCmd {
+				assert.Same(t, rootCmd.PersistentFlags().Lookup(name), flag, "--%s of %s", name, cmd.CommandPath())
+			}
+		}
+		assert.Equal(t, "output", cmd.Flags().ShorthandLookup("o").Name, cmd.CommandPath())
+		for _, child := range cmd.Commands() {
+			check(child)
+		}
+	}
+	check(rootCmd)
+}
@@ -117,6 +117,8 @@ Type 'yontrack validate --help' to get a list of all options.
 				}) {
 					errors {
 						message
+						exception
+						location
 					}
 				}
 			}
@@ -167,9 +169,7 @@ Type 'yontrack validate --help' to get a list of all options.
 		// Mutation payload
 		var payload struct {
 			CreateValidationRun struct {
-				Errors []struct {
-					Message string
-				}
+				Errors []client.GraphQLError
 			}
 		}
 
@@ -210,5 +210,5 @@ func init() {
 	// is called directly, e.g.:
 	validateCmd.Flags().StringP("status", "s", "", "ID of the status (required if no data)")
 	validateCmd.Flags().StringP("data-type", "t", "", "FQCN of the validation data type")
-	validateCmd.Flags().StringP("data", "o", "", "JSON representation of the validation data")
+	validateCmd.Flags().StringP("data", "", "", "JSON representation of the validation data")
 }
@@ -95,9 +95,7 @@ For example:
 		// Mutation payload
 		var payload struct {
 			ValidateBuildWithCHML struct {
-				Errors []struct {
-					Message string
-				}
+				Errors []client.GraphQLError
 			}
 		}
 
@@ -129,6 +127,8 @@ For example:
 				}) {
 					errors {
 						message
+						exception
+						location
 					}
 				}
 			}
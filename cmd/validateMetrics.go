@@ -113,9 +113,7 @@ An alternative syntax is:
 		// Mutation payload
 		var payload struct {
 			ValidateBuildWithMetrics struct {
-				Errors []struct {
-					Message string
-				}
+				Errors []client.GraphQLError
 			}
 		}
 
@@ -141,6 +139,8 @@ An alternative syntax is:
 				}) {
 					errors {
 						message
+						exception
+						location
 					}
 				}
 			}
@@ -76,9 +76,7 @@ For example:
 		// Mutation payload
 		var payload struct {
 			ValidateBuildWithPercentage struct {
-				Errors []struct {
-					Message string
-				}
+				Errors []client.GraphQLError
 			}
 		}
 
@@ -104,6 +102,8 @@ For example:
 				}) {
 					errors {
 						message
+						exception
+						location
 					}
 				}
 			}
@@ -73,9 +73,7 @@ func SetupTestValidationStamp(
 
 	var data struct {
 		SetupTestSummaryValidationStamp struct {
-			Errors []struct {
-				Message string
-			}
+			Errors []client.GraphQLError
 		}
 	}
 
@@ -98,6 +96,8 @@ func SetupTestValidationStamp(
 			}) {
 				errors {
 					message
+					exception
+					location
 				}
 			}
 		}
@@ -79,9 +79,7 @@ For example:
 
 		var data struct {
 			SetupCHMLValidationStamp struct {
-				Errors []struct {
-					Message string
-				}
+				Errors []client.GraphQLError
 			}
 		}
 		if err := client.GraphQLCall(cfg, `
@@ -111,6 +109,8 @@ For example:
 				}) {
 					errors {
 						message
+						exception
+						location
 					}
 				}
 			}
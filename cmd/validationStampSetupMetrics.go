@@ -62,9 +62,7 @@ For example:
 
 		var data struct {
 			SetupMetricsValidationStamp struct {
-				Errors []struct {
-					Message string
-				}
+				Errors []client.GraphQLError
 			}
 		}
 		if err := client.GraphQLCall(cfg, `
@@ -82,6 +80,8 @@ For example:
 				}) {
 					errors {
 						message
+						exception
+						location
 					}
 				}
 			}
@@ -91,9 +91,7 @@ For example:
 
 		var data struct {
 			SetupPercentageValidationStamp struct {
-				Errors []struct {
-					Message string
-				}
+				Errors []client.GraphQLError
 			}
 		}
 		if err := client.GraphQLCall(cfg, `
@@ -117,6 +115,8 @@ For example:
 				}) {
 					errors {
 						message
+						exception
+						location
 					}
 				}
 			}
@@ -155,6 +155,6 @@ func init() {
 	// validationStampSetupPercentageCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 	validationStampSetupPercentageCmd.Flags().IntP("warning", "w", 0, "Threshold value for a warning")
 	validationStampSetupPercentageCmd.Flags().IntP("failure", "f", 0, "Threshold value for a failure")
-	validationStampSetupPercentageCmd.Flags().BoolP("ok-if-greater", "o", false, "Direction of the value scale")
+	validationStampSetupPercentageCmd.Flags().BoolP("ok-if-greater", "", false, "Direction of the value scale")
 
 }
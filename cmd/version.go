@@ -24,11 +24,13 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"io"
 
 	"github.com/spf13/cobra"
 
 	client "yontrack/client"
 	config "yontrack/config"
+	"yontrack/output"
 )
 
 var versionCli bool
@@ -49,13 +51,21 @@ To display only the CLI version, run:
 To display only the Ontrack version, run:
 
 	yontrack version --ontrack
+
+The version information can also be rendered using the --output flag:
+
+	yontrack version --output json
 `,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return version()
+		return version(cmd)
 	},
 }
 
-func version() error {
+func version(cmd *cobra.Command) error {
+	printer, err := output.FromCommand(cmd)
+	if err != nil {
+		return err
+	}
 	both := (versionCli && versionOntrack) || (!versionCli && !versionOntrack)
 	var ontrackVersion string
 	var ontrackURL string
@@ -88,18 +98,41 @@ func version() error {
 
 		ontrackVersion = data.Info.Version.Display
 	}
-	if both {
-		fmt.Printf("CLI Version %s\n", config.Version)
-		fmt.Printf("Ontrack URL %s\n", ontrackURL)
-		fmt.Printf("Ontrack Version %s\n", ontrackVersion)
-	} else if versionCli {
-		fmt.Println(config.Version)
-	} else if versionOntrack {
-		fmt.Println(ontrackVersion)
-	} else {
-		return errors.New("No version was asked")
+	info := versionInfo{
+		CLI:            config.Version,
+		OntrackURL:     ontrackURL,
+		OntrackVersion: ontrackVersion,
 	}
-	return nil
+	return printer.Print(&output.Result{
+		Value:   info,
+		Headers: []string{"CLI", "ONTRACK URL", "ONTRACK VERSION"},
+		Rows:    [][]string{{info.CLI, info.OntrackURL, info.OntrackVersion}},
+		Env: []output.Variable{
+			{Name: "YONTRACK_CLI_VERSION", Value: info.CLI},
+			{Name: "YONTRACK_URL", Value: info.OntrackURL},
+			{Name: "YONTRACK_VERSION", Value: info.OntrackVersion},
+		},
+		Plain: func(w io.Writer) error {
+			if both {
+				_, _ = fmt.Fprintf(w, "CLI Version %s\n", info.CLI)
+				_, _ = fmt.Fprintf(w, "Ontrack URL %s\n", info.OntrackURL)
+				_, _ = fmt.Fprintf(w, "Ontrack Version %s\n", info.OntrackVersion)
+			} else if versionCli {
+				_, _ = fmt.Fprintln(w, info.CLI)
+			} else if versionOntrack {
+				_, _ = fmt.Fprintln(w, info.OntrackVersion)
+			} else {
+				return errors.New("No version was asked")
+			}
+			return nil
+		},
+	})
+}
+
+type versionInfo struct {
+	CLI            string `json:"cli"`
+	OntrackURL     string `json:"ontrackUrl,omitempty"`
+	OntrackVersion string `json:"ontrackVersion,omitempty"`
 }
 
 func init() {
@@ -114,5 +147,5 @@ func init() {
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
 	versionCmd.Flags().BoolVarP(&versionCli, "cli", "c", false, "Displays the CLI version")
-	versionCmd.Flags().BoolVarP(&versionOntrack, "ontrack", "o", false, "Displays the Ontrack version for the current configuration")
+	versionCmd.Flags().BoolVarP(&versionOntrack, "ontrack", "", false, "Displays the Ontrack version for the current configuration")
 }
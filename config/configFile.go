@@ -0,0 +1,178 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ConfigVersion is the version of the format of the configuration file written by this CLI.
+// Files without any version are version 0 and are migrated when read.
+const ConfigVersion = 1
+
+// Migrations of the configuration file, indexed by the version they migrate from
+var configMigrations = []func(root *RootConfig) error{
+	// 0 -> 1: introduction of the version field, nothing to change
+	func(root *RootConfig) error { return nil },
+}
+
+// How long to wait for the lock of the configuration file or of the credential store
+var configLockTimeout = 10 * time.Second
+
+// Age after which a lock is considered as left over by a crashed process
+const configLockStaleAge = 30 * time.Second
+
+// parseRootConfiguration reads the content of the configuration file and migrates it to the current version
+func parseRootConfiguration(path string, content []byte) (*RootConfig, error) {
+	var root RootConfig
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return &root, newConfigurationError("Cannot parse the configuration file %s: %s", path, err)
+	}
+	if root.Version > ConfigVersion {
+		return &root, newConfigurationError("The configuration file %s has version %d, which is not supported by this version of the CLI (max. %d)", path, root.Version, ConfigVersion)
+	}
+	for root.Version < ConfigVersion {
+		if err := configMigrations[root.Version](&root); err != nil {
+			return &root, newConfigurationError("Cannot migrate the configuration file %s from version %d: %s", path, root.Version, err)
+		}
+		root.Version++
+	}
+	return &root, nil
+}
+
+// updateRootConfiguration reads, changes and saves back the configuration file while holding its lock,
+// so that concurrent invocations of the CLI do not lose their changes
+func updateRootConfiguration(update func(root *RootConfig) error) error {
+	path, err := getConfigFilePath()
+	if err != nil {
+		return err
+	}
+	// The user configuration directory may not exist yet
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return newConfigurationError("Cannot write the configuration file %s: %s", path, err)
+	}
+	unlock, err := lockConfigFile(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	root, err := ReadRootConfiguration()
+	if err != nil {
+		return err
+	}
+	if err := update(root); err != nil {
+		return err
+	}
+	return writeRootConfiguration(path, root)
+}
+
+// writeRootConfiguration saves the configuration, replacing the file atomically
+func writeRootConfiguration(path string, root *RootConfig) error {
+	root.Version = ConfigVersion
+	buf, err := yaml.Marshal(root)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomically(path, buf); err != nil {
+		return newConfigurationError("Cannot write the configuration file %s: %s", path, err)
+	}
+	return nil
+}
+
+// writeFileAtomically writes the content into a temporary file which then replaces the
+// file at path, so that the file is never partially written. The file is only readable
+// by the current user.
+func writeFileAtomically(path string, content []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmp := file.Name()
+	err = file.Chmod(0600)
+	if err == nil {
+		_, err = file.Write(content)
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+	}
+	return err
+}
+
+// lockConfigFile locks the configuration file, waiting for any other process holding it
+func lockConfigFile(path string) (func(), error) {
+	unlock, err := lockFile(path)
+	if err != nil {
+		return nil, newConfigurationError("Cannot lock the configuration file %s: %s", path, err)
+	}
+	return unlock, nil
+}
+
+// lockFile creates the <path>.lock file, waiting for any other process holding it.
+// The returned function releases the lock.
+func lockFile(path string) (func(), error) {
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(configLockTimeout)
+	for {
+		file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			_, _ = file.WriteString(strconv.Itoa(os.Getpid()))
+			_ = file.Close()
+			return func() { _ = os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		// Removes the lock left over by a crashed process
+		if isStaleLock(lockPath) && removeStaleLock(lockPath) {
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s is held by another process", lockPath)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// isStaleLock returns true if the lock exists and is older than configLockStaleAge
+func isStaleLock(lockPath string) bool {
+	info, err := os.Stat(lockPath)
+	return err == nil && time.Since(info.ModTime()) > configLockStaleAge
+}
+
+// removeStaleLock removes a lock left over by a crashed process and returns true if it did.
+//
+// The lock is removed while holding a second <path>.lock.break lock, and only if it is still stale,
+// so that two processes which both saw the stale lock cannot both remove it, the second one deleting
+// the lock just created by the first one.
+func removeStaleLock(lockPath string) bool {
+	breaker := lockPath + ".break"
+	file, err := os.OpenFile(breaker, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		// Another process is removing the stale lock, unless it crashed while doing so
+		if isStaleLock(breaker) {
+			_ = os.Remove(breaker)
+		}
+		return false
+	}
+	_ = file.Close()
+	defer func() { _ = os.Remove(breaker) }()
+	if !isStaleLock(lockPath) {
+		return false
+	}
+	return os.Remove(lockPath) == nil
+}
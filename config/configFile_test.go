@@ -0,0 +1,166 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func useConfigFile(t *testing.T) string {
+	previous := ConfigFilePath
+	t.Cleanup(func() {
+		ConfigFilePath = previous
+	})
+	ConfigFilePath = filepath.Join(t.TempDir(), "config.yaml")
+	return ConfigFilePath
+}
+
+func TestConfigFile_Version(t *testing.T) {
+	path := useConfigFile(t)
+
+	// Files without version are migrated
+	require.NoError(t, os.WriteFile(path, []byte("selected: prod\nconfigurations:\n  - name: prod\n"), 0600))
+	root, err := ReadRootConfiguration()
+	require.NoError(t, err)
+	assert.Equal(t, ConfigVersion, root.Version)
+	assert.Equal(t, "prod", root.Selected)
+
+	require.NoError(t, SetConfigurationState("prod", true))
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), fmt.Sprintf("version: %d\n", ConfigVersion))
+
+	require.NoError(t, os.WriteFile(path, []byte("version: 99\n"), 0600))
+	_, err = ReadRootConfiguration()
+	var configurationError *ConfigurationError
+	require.True(t, errors.As(err, &configurationError))
+	assert.Contains(t, err.Error(), "has version 99, which is not supported")
+}
+
+func TestConfigFile_AtomicWrite(t *testing.T) {
+	path := useConfigFile(t)
+
+	require.NoError(t, AddConfiguration(Config{Name: "prod", URL: "https://prod.example.com"}, false))
+	require.NoError(t, AddConfiguration(Config{Name: "staging", URL: "https://staging.example.com"}, false))
+	require.NoError(t, SetSelectedConfiguration("prod"))
+	require.NoError(t, DeleteConfiguration("staging"))
+
+	root, err := ReadRootConfiguration()
+	require.NoError(t, err)
+	assert.Equal(t, "prod", root.Selected)
+	require.Len(t, root.Configurations, 1)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	if os.PathSeparator == '/' {
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+	// Neither temporary files nor the lock are left over
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestConfigFile_WriteErrors(t *testing.T) {
+	previous := ConfigFilePath
+	t.Cleanup(func() {
+		ConfigFilePath = previous
+	})
+	// The parent directory cannot be created
+	parent := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(parent, nil, 0600))
+	ConfigFilePath = filepath.Join(parent, "config.yaml")
+
+	err := AddConfiguration(Config{Name: "prod"}, false)
+	var configurationError *ConfigurationError
+	require.True(t, errors.As(err, &configurationError))
+	assert.Contains(t, err.Error(), "Cannot write the configuration file")
+}
+
+func TestConfigFile_ConcurrentUpdates(t *testing.T) {
+	useConfigFile(t)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for index := 0; index < 20; index++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			errs <- AddConfiguration(Config{Name: fmt.Sprintf("config-%d", index)}, false)
+		}(index)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	root, err := ReadRootConfiguration()
+	require.NoError(t, err)
+	assert.Len(t, root.Configurations, 20)
+}
+
+func TestConfigFile_Lock(t *testing.T) {
+	path := useConfigFile(t)
+	previousTimeout := configLockTimeout
+	t.Cleanup(func() {
+		configLockTimeout = previousTimeout
+	})
+	configLockTimeout = 100 * time.Millisecond
+
+	unlock, err := lockConfigFile(path)
+	require.NoError(t, err)
+	err = AddConfiguration(Config{Name: "prod"}, false)
+	assert.EqualError(t, err, fmt.Sprintf("Cannot lock the configuration file %s: %s.lock is held by another process", path, path))
+	unlock()
+
+	// Stale locks are removed
+	require.NoError(t, os.WriteFile(path+".lock", []byte("1"), 0600))
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(path+".lock", old, old))
+	require.NoError(t, AddConfiguration(Config{Name: "prod"}, false))
+}
+
+func TestConfigFile_StaleLockRace(t *testing.T) {
+	path := useConfigFile(t)
+	lockPath := path + ".lock"
+	require.NoError(t, os.WriteFile(lockPath, []byte("1"), 0600))
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(lockPath, old, old))
+
+	// All the waiters see the stale lock, but only one of them holds the lock at a time
+	var holders, maxHolders int32
+	var wg sync.WaitGroup
+	for index := 0; index < 10; index++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock, err := lockFile(path)
+			if !assert.NoError(t, err) {
+				return
+			}
+			current := atomic.AddInt32(&holders, 1)
+			for {
+				previous := atomic.LoadInt32(&maxHolders)
+				if current <= previous || atomic.CompareAndSwapInt32(&maxHolders, previous, current) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&holders, -1)
+			unlock()
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), maxHolders)
+	_, err := os.Stat(lockPath + ".break")
+	assert.True(t, os.IsNotExist(err))
+}
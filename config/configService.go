@@ -1,17 +1,15 @@
 package config
 
 import (
-	"errors"
 	"fmt"
-	"io"
-	"io/ioutil"
 	"os"
-
-	"gopkg.in/yaml.v2"
+	"strings"
 )
 
 // Root configuration
 type RootConfig struct {
+	// Version of the format of the file, see ConfigVersion
+	Version int
 	// Default configuration name
 	Selected string
 	// List of configurations
@@ -26,14 +24,20 @@ type Config struct {
 	URL string
 	// Username for the remote server (when using basic authentication)
 	Username string
-	// Password for the remote server (when using basic authentication)
+	// Password for the remote server (when using basic authentication).
+	// Can be a reference like env:NAME, file:PATH or store:NAME.
 	Password string
-	// Token for the remote server (when using token-based authentication)
+	// Token for the remote server (when using token-based authentication).
+	// Can be a reference like env:NAME, file:PATH or store:NAME.
 	Token string
+	// git-credential style command used to get the credentials when neither a password nor a token is set
+	CredentialHelper string `yaml:"credentialHelper,omitempty"`
 	// Is this configuration disabled?
 	Disabled bool
 	// Connection retry configuration
 	ConnectionRetry `yaml:"connectionRetry"`
+	// Timeout in seconds for each call to the remote server (0 for no timeout)
+	TimeoutSec int `yaml:"timeoutSec"`
 }
 
 type ConnectionRetry struct {
@@ -41,84 +45,124 @@ type ConnectionRetry struct {
 	MaxCount       int `yaml:"maxcount"`
 }
 
-// Gets the current configuration
+// ConfigurationError is returned when no usable configuration can be found or read
+type ConfigurationError struct {
+	Message string
+}
+
+func (e *ConfigurationError) Error() string {
+	return e.Message
+}
+
+func newConfigurationError(format string, args ...interface{}) error {
+	return &ConfigurationError{Message: fmt.Sprintf(format, args...)}
+}
+
+// Gets the configuration to use, with its credentials resolved:
+//
+// * the configuration named by the --context flag, if any
+// * the configuration defined by the YONTRACK_* environment variables, if YONTRACK_URL is set
+// * the selected configuration of the configuration file
 func GetSelectedConfiguration() (*Config, error) {
-	root, err := ReadRootConfiguration()
+	if ContextName != "" {
+		return GetConfiguration(ContextName)
+	}
+	cfg, err := ConfigFromEnv(os.LookupEnv)
 	if err != nil {
 		return nil, err
 	}
-	selected := root.Selected
-	if selected != "" {
-		for _, item := range root.Configurations {
-			if item.Name == selected {
-				return &item, nil
-			}
+	if cfg != nil {
+		if err := cfg.ResolveCredentials(); err != nil {
+			return nil, err
 		}
-		return nil, fmt.Errorf("No configuration named %s", selected)
+		return cfg, nil
+	}
+	root, err := ReadRootConfiguration()
+	if err != nil {
+		return nil, err
+	}
+	if root.Selected == "" {
+		return nil, newConfigurationError("No current configuration")
+	}
+	return getConfiguration(root, root.Selected)
+}
+
+// Gets a configuration of the configuration file by name, with its credentials resolved,
+// without changing the selected configuration
+func GetConfiguration(name string) (*Config, error) {
+	root, err := ReadRootConfiguration()
+	if err != nil {
+		return nil, err
+	}
+	return getConfiguration(root, name)
+}
+
+func getConfiguration(root *RootConfig, name string) (*Config, error) {
+	item := findConfigurationByName(root, name)
+	if item == nil {
+		return nil, newConfigurationError("No configuration named %s", name)
+	}
+	if err := item.ResolveCredentials(); err != nil {
+		return nil, err
 	}
-	return nil, errors.New("No current configuration")
+	return item, nil
 }
 
 // Reads the configuration
 func ReadRootConfiguration() (*RootConfig, error) {
-	var root RootConfig
 	configFilePath, err := getConfigFilePath()
 	if err != nil {
 		return nil, err
 	}
 
 	// If the config file does not exist, returns an empty root config
-	if _, err := os.Stat(configFilePath); err != nil {
-		if os.IsNotExist(err) {
-			return &root, nil
-		}
+	buf, err := os.ReadFile(configFilePath)
+	if os.IsNotExist(err) {
+		return &RootConfig{Version: ConfigVersion}, nil
+	} else if err != nil {
+		return nil, newConfigurationError("Cannot read the configuration file %s: %s", configFilePath, err)
 	}
-
-	reader, _ := os.Open(configFilePath)
-	buf, _ := io.ReadAll(reader)
-	err = yaml.Unmarshal(buf, &root)
-	return &root, err
+	return parseRootConfiguration(configFilePath, buf)
 }
 
 // Adds a new configuration and set as default
 func AddConfiguration(config Config, override bool) error {
-	root, err := ReadRootConfiguration()
-	if err != nil {
-		return err
-	}
-	configurations := root.Configurations
-	existing := false
-	// Check if the configuration name already exists
-	for index, item := range configurations {
-		if item.Name == config.Name {
-			if override {
-				configurations[index] = config
-				existing = true
-			} else {
-				return fmt.Errorf("Configuration with name %s already exists", config.Name)
+	return updateRootConfiguration(func(root *RootConfig) error {
+		existing := false
+		// Check if the configuration name already exists
+		for index, item := range root.Configurations {
+			if item.Name == config.Name {
+				if override {
+					root.Configurations[index] = config
+					existing = true
+				} else {
+					return newConfigurationError("Configuration with name %s already exists", config.Name)
+				}
 			}
 		}
-	}
-	// Default selected configuration is the added one
-	// Adds the configuration to the list if not existing already
+		// Adds the configuration to the list if not existing already
+		if !existing {
+			root.Configurations = append(root.Configurations, config)
+		}
+		// Default selected configuration is the added one
+		root.Selected = config.Name
+		return nil
+	})
+}
 
-	if !existing {
-		configurations = append(root.Configurations, config)
+// CheckConfigurationCanBeAdded returns an error if a configuration with this name already exists
+// and is not to be overridden, before anything is done for this configuration
+func CheckConfigurationCanBeAdded(name string, override bool) error {
+	if override {
+		return nil
 	}
-	newRoot := RootConfig{
-		Selected:       config.Name,
-		Configurations: configurations,
-	}
-	// Saves the root configuration back
-	configFilePath, err := getConfigFilePath()
+	root, err := ReadRootConfiguration()
 	if err != nil {
 		return err
 	}
-	buf, _ := yaml.Marshal(newRoot)
-	_, _ = os.OpenFile(configFilePath, os.O_CREATE|os.O_WRONLY, 0600)
-	_ = ioutil.WriteFile(configFilePath, buf, 0600)
-
-	// OK
+	if findConfigurationByName(root, name) != nil {
+		return newConfigurationError("Configuration with name %s already exists", name)
+	}
 	return nil
 }
 
@@ -143,97 +187,67 @@ func replaceConfigurationByName(root *RootConfig, config *Config) {
 
 // Sets the new selected configuration
 func SetSelectedConfiguration(name string) error {
-	root, err := ReadRootConfiguration()
-	if err != nil {
-		return err
-	}
-	existing := findConfigurationByName(root, name)
-	if existing == nil {
-		return fmt.Errorf("Configuration with name %s does not exist", name)
-	}
-	newRoot := RootConfig{
-		Selected:       name,
-		Configurations: root.Configurations,
-	}
-	// Saves the root configuration back
-	configFilePath, err := getConfigFilePath()
-	if err != nil {
-		return err
-	}
-	buf, _ := yaml.Marshal(newRoot)
-	_, _ = os.OpenFile(configFilePath, os.O_CREATE|os.O_WRONLY, 0600)
-	_ = os.WriteFile(configFilePath, buf, 0600)
-
-	// OK
-	return nil
+	return updateRootConfiguration(func(root *RootConfig) error {
+		if findConfigurationByName(root, name) == nil {
+			return newConfigurationError("Configuration with name %s does not exist", name)
+		}
+		root.Selected = name
+		return nil
+	})
 }
 
 // Disables or enabled a configuration
 func SetConfigurationState(name string, disabled bool) error {
-	root, err := ReadRootConfiguration()
-	if err != nil {
-		return err
-	}
-	existing := findConfigurationByName(root, name)
-	if existing == nil {
-		return fmt.Errorf("Configuration with name %s does not exist", name)
-	}
-	// Adjust the existing configuration
-	existing.Disabled = disabled
-	replaceConfigurationByName(root, existing)
-	// Saves the root configuration back
-	configFilePath, err := getConfigFilePath()
-	if err != nil {
-		return err
-	}
-	buf, _ := yaml.Marshal(root)
-	_, _ = os.OpenFile(configFilePath, os.O_CREATE|os.O_WRONLY, 0600)
-	_ = os.WriteFile(configFilePath, buf, 0600)
-
-	// OK
-	return nil
+	return updateRootConfiguration(func(root *RootConfig) error {
+		existing := findConfigurationByName(root, name)
+		if existing == nil {
+			return newConfigurationError("Configuration with name %s does not exist", name)
+		}
+		// Adjust the existing configuration
+		existing.Disabled = disabled
+		replaceConfigurationByName(root, existing)
+		return nil
+	})
 }
 
 // Deletes an existing configuration
 func DeleteConfiguration(name string) error {
-	root, err := ReadRootConfiguration()
+	var deleted *Config
+	err := updateRootConfiguration(func(root *RootConfig) error {
+		deleted = findConfigurationByName(root, name)
+		if deleted == nil {
+			return newConfigurationError("Configuration with name %s does not exist", name)
+		}
+		// Filter out the deleted configuration
+		configurations := make([]Config, 0, len(root.Configurations)-1)
+		for _, item := range root.Configurations {
+			if item.Name != name {
+				configurations = append(configurations, item)
+			}
+		}
+		root.Configurations = configurations
+		// Clear selected if it was the deleted config
+		if root.Selected == name {
+			root.Selected = ""
+		}
+		return nil
+	})
 	if err != nil {
 		return err
 	}
-	existing := findConfigurationByName(root, name)
-	if existing == nil {
-		return fmt.Errorf("Configuration with name %s does not exist", name)
-	}
-	// Filter out the deleted configuration
-	configurations := make([]Config, 0, len(root.Configurations)-1)
-	for _, item := range root.Configurations {
-		if item.Name != name {
-			configurations = append(configurations, item)
+	// Removes the credentials of the configuration from the local store
+	for _, credential := range []string{deleted.Password, deleted.Token} {
+		if strings.HasPrefix(credential, CredentialStorePrefix) {
+			if store, err := DefaultCredentialStore(); err == nil {
+				_ = store.Delete(strings.TrimPrefix(credential, CredentialStorePrefix))
+			}
 		}
 	}
-	// Clear selected if it was the deleted config
-	selected := root.Selected
-	if selected == name {
-		selected = ""
-	}
-	newRoot := RootConfig{
-		Selected:       selected,
-		Configurations: configurations,
-	}
-	// Saves the root configuration back
-	configFilePath, err := getConfigFilePath()
-	if err != nil {
-		return err
-	}
-	buf, _ := yaml.Marshal(newRoot)
-	_, _ = os.OpenFile(configFilePath, os.O_CREATE|os.O_WRONLY, 0600)
-	_ = os.WriteFile(configFilePath, buf, 0600)
-
-	// OK
 	return nil
 }
 
 // Gets the path to the configuration file
 func getConfigFilePath() (string, error) {
-	return ConfigFilePath, nil
+	path, _, err := ResolveConfigFilePath()
+	return path, err
 }
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Prefixes of the credential references, which can be used instead of the actual password or token
+const (
+	// env:NAME reads the credential from the NAME environment variable
+	CredentialEnvPrefix = "env:"
+	// file:PATH reads the credential from a file, like a Docker or Kubernetes secret
+	CredentialFilePrefix = "file:"
+	// store:NAME reads the credential from the encrypted local store
+	CredentialStorePrefix = "store:"
+)
+
+// IsCredentialReference checks if a password or a token refers to a credential stored elsewhere
+func IsCredentialReference(value string) bool {
+	return strings.HasPrefix(value, CredentialEnvPrefix) ||
+		strings.HasPrefix(value, CredentialFilePrefix) ||
+		strings.HasPrefix(value, CredentialStorePrefix)
+}
+
+// ResolveCredentials replaces the credential references of the configuration by their values.
+// When neither a token nor a password is set, the credentials are asked to the credential helper, if any.
+func (c *Config) ResolveCredentials() error {
+	var err error
+	if c.Password, err = resolveCredential(c.Password); err != nil {
+		return newConfigurationError("Cannot get the password of the %s configuration: %s", c.Name, err)
+	}
+	if c.Token, err = resolveCredential(c.Token); err != nil {
+		return newConfigurationError("Cannot get the token of the %s configuration: %s", c.Name, err)
+	}
+	if c.CredentialHelper != "" && c.Token == "" && c.Password == "" {
+		username, password, err := runCredentialHelper(c.CredentialHelper, c.URL, c.Username)
+		if err != nil {
+			return newConfigurationError("Cannot get the credentials of the %s configuration: %s", c.Name, err)
+		}
+		if username != "" {
+			c.Username = username
+		}
+		// Without any username, the password is a token
+		if c.Username != "" {
+			c.Password = password
+		} else {
+			c.Token = password
+		}
+	}
+	return nil
+}
+
+func resolveCredential(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, CredentialEnvPrefix):
+		name := strings.TrimPrefix(value, CredentialEnvPrefix)
+		credential, ok := os.LookupEnv(name)
+		if !ok || credential == "" {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		return credential, nil
+	case strings.HasPrefix(value, CredentialFilePrefix):
+		path := strings.TrimPrefix(value, CredentialFilePrefix)
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(content), "\r\n"), nil
+	case strings.HasPrefix(value, CredentialStorePrefix):
+		store, err := DefaultCredentialStore()
+		if err != nil {
+			return "", err
+		}
+		return store.Get(strings.TrimPrefix(value, CredentialStorePrefix))
+	default:
+		return value, nil
+	}
+}
+
+// runCredentialHelper gets the credentials for an URL using a git-credential style helper.
+//
+// The helper is run through the shell with the "get" argument. It receives the protocol,
+// the host and the optional username on its standard input, and must print the username
+// (optional) and the password using the same key=value format.
+func runCredentialHelper(helper string, serverURL string, username string) (string, string, error) {
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return "", "", err
+	}
+	var input bytes.Buffer
+	fmt.Fprintf(&input, "protocol=%s\nhost=%s\n", u.Scheme, u.Host)
+	if path := strings.Trim(u.Path, "/"); path != "" {
+		fmt.Fprintf(&input, "path=%s\n", path)
+	}
+	if username != "" {
+		fmt.Fprintf(&input, "username=%s\n", username)
+	}
+	input.WriteString("\n")
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", helper+" get")
+	} else {
+		cmd = exec.Command("sh", "-c", helper+" get")
+	}
+	cmd.Stdin = &input
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", "", fmt.Errorf("credential helper failed: %w", err)
+	}
+
+	var password string
+	found := false
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		switch parts[0] {
+		case "username":
+			username = parts[1]
+		case "password":
+			password = parts[1]
+			found = true
+		}
+	}
+	if !found {
+		return "", "", fmt.Errorf("no password returned by the credential helper")
+	}
+	return username, password, nil
+}
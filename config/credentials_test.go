@@ -0,0 +1,153 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveCredentials_EnvAndFile(t *testing.T) {
+	t.Setenv("TEST_ONTRACK_TOKEN", "from-env")
+	secret := filepath.Join(t.TempDir(), "password")
+	require.NoError(t, os.WriteFile(secret, []byte("from-file\n"), 0600))
+
+	cfg := Config{Name: "test", Username: "admin", Password: "file:" + secret, Token: "env:TEST_ONTRACK_TOKEN"}
+	require.NoError(t, cfg.ResolveCredentials())
+	assert.Equal(t, "from-file", cfg.Password)
+	assert.Equal(t, "from-env", cfg.Token)
+
+	plain := Config{Name: "plain", Token: "abc"}
+	require.NoError(t, plain.ResolveCredentials())
+	assert.Equal(t, "abc", plain.Token)
+}
+
+func TestResolveCredentials_Errors(t *testing.T) {
+	cfg := Config{Name: "test", Token: "env:TEST_ONTRACK_UNDEFINED"}
+	err := cfg.ResolveCredentials()
+	var configurationError *ConfigurationError
+	require.True(t, errors.As(err, &configurationError))
+	assert.Equal(t, "Cannot get the token of the test configuration: environment variable TEST_ONTRACK_UNDEFINED is not set", err.Error())
+
+	cfg = Config{Name: "test", Password: "file:/nonexistent/password"}
+	assert.Error(t, cfg.ResolveCredentials())
+}
+
+func TestCredentialStore(t *testing.T) {
+	dir := t.TempDir()
+	store := &CredentialStore{Path: filepath.Join(dir, "credentials")}
+
+	// The passphrase is required
+	t.Setenv("YONTRACK_CREDENTIALS_KEY", "")
+	err := store.Set("prod.token", "secret-token")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "the credential store requires the YONTRACK_CREDENTIALS_KEY environment variable")
+	t.Setenv("YONTRACK_CREDENTIALS_KEY", "passphrase")
+
+	_, err = store.Get("prod.token")
+	assert.EqualError(t, err, "no credential named prod.token in the store")
+
+	require.NoError(t, store.Set("prod.token", "secret-token"))
+	require.NoError(t, store.Set("local.password", "secret-password"))
+	value, err := store.Get("prod.token")
+	require.NoError(t, err)
+	assert.Equal(t, "secret-token", value)
+
+	// The credentials are not stored in clear
+	content, err := os.ReadFile(store.Path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "secret-token")
+
+	require.NoError(t, store.Delete("prod.token"))
+	_, err = store.Get("prod.token")
+	assert.Error(t, err)
+	value, err = store.Get("local.password")
+	require.NoError(t, err)
+	assert.Equal(t, "secret-password", value)
+
+	// Only the encrypted store is written, the key is never saved
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "credentials", entries[0].Name())
+
+	// Another key cannot decrypt the store
+	t.Setenv("YONTRACK_CREDENTIALS_KEY", "other")
+	_, err = store.Get("local.password")
+	assert.EqualError(t, err, "cannot decrypt the credential store (wrong key?)")
+}
+
+func TestCredentialStore_ConcurrentUpdates(t *testing.T) {
+	t.Setenv("YONTRACK_CREDENTIALS_KEY", "passphrase")
+	store := &CredentialStore{Path: filepath.Join(t.TempDir(), "credentials")}
+
+	var wg sync.WaitGroup
+	for index := 0; index < 5; index++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			assert.NoError(t, store.Set(fmt.Sprintf("config-%d.token", index), "token"))
+		}(index)
+	}
+	wg.Wait()
+
+	for index := 0; index < 5; index++ {
+		_, err := store.Get(fmt.Sprintf("config-%d.token", index))
+		assert.NoError(t, err)
+	}
+}
+
+func TestResolveCredentials_Store(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("YONTRACK_CREDENTIALS_KEY", "passphrase")
+	store, err := DefaultCredentialStore()
+	require.NoError(t, err)
+	require.NoError(t, store.Set("prod.token", "stored"))
+
+	cfg := Config{Name: "prod", Token: "store:prod.token"}
+	require.NoError(t, cfg.ResolveCredentials())
+	assert.Equal(t, "stored", cfg.Token)
+}
+
+func TestResolveCredentials_Helper(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script helper")
+	}
+	dir := t.TempDir()
+	helper := filepath.Join(dir, "helper.sh")
+	// Returns a username only for the basic authentication, and echoes the input to a file
+	require.NoError(t, os.WriteFile(helper, []byte(`#!/bin/sh
+[ "$1" = "get" ] || exit 1
+cat > "`+filepath.Join(dir, "input")+`"
+grep -q '^username=' "`+filepath.Join(dir, "input")+`" && echo "username=other"
+echo "password=from-helper"
+`), 0700))
+
+	cfg := Config{Name: "prod", URL: "https://ontrack.example.com/ontrack", CredentialHelper: helper}
+	require.NoError(t, cfg.ResolveCredentials())
+	assert.Equal(t, "from-helper", cfg.Token)
+	assert.Equal(t, "", cfg.Password)
+	input, err := os.ReadFile(filepath.Join(dir, "input"))
+	require.NoError(t, err)
+	assert.Equal(t, "protocol=https\nhost=ontrack.example.com\npath=ontrack\n\n", string(input))
+
+	cfg = Config{Name: "local", URL: "http://localhost:8080", Username: "admin", CredentialHelper: helper}
+	require.NoError(t, cfg.ResolveCredentials())
+	assert.Equal(t, "other", cfg.Username)
+	assert.Equal(t, "from-helper", cfg.Password)
+	assert.Equal(t, "", cfg.Token)
+
+	// The helper is not used when the credentials are known
+	cfg = Config{Name: "token", Token: "abc", CredentialHelper: "false"}
+	require.NoError(t, cfg.ResolveCredentials())
+	assert.Equal(t, "abc", cfg.Token)
+
+	cfg = Config{Name: "failing", URL: "http://localhost:8080", CredentialHelper: "false"}
+	assert.Error(t, cfg.ResolveCredentials())
+}
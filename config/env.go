@@ -0,0 +1,81 @@
+package config
+
+import (
+	"strconv"
+)
+
+// Environment variables used to define a configuration without any configuration file
+const (
+	// URL of the remote server. The configuration is defined by the environment only when this variable is set.
+	EnvURL = "YONTRACK_URL"
+	// Token for the remote server. Can be a reference like file:PATH.
+	EnvToken = "YONTRACK_TOKEN"
+	// Username for the remote server (when using basic authentication)
+	EnvUsername = "YONTRACK_USERNAME"
+	// Password for the remote server (when using basic authentication). Can be a reference like file:PATH.
+	EnvPassword = "YONTRACK_PASSWORD"
+	// Name of the configuration, as displayed in the messages
+	EnvConfigName = "YONTRACK_CONFIG_NAME"
+	// Max connection retry attempts
+	EnvConnRetryCount = "YONTRACK_CONN_RETRY_COUNT"
+	// Max connection retry wait time between attempts in seconds
+	EnvConnRetryWait = "YONTRACK_CONN_RETRY_WAIT"
+	// Timeout in seconds for each call to the remote server
+	EnvTimeout = "YONTRACK_TIMEOUT"
+)
+
+// DefaultEnvConfigName is the name of the configuration defined by the environment when YONTRACK_CONFIG_NAME is not set
+const DefaultEnvConfigName = "env"
+
+// ConfigFromEnv creates a configuration from the YONTRACK_* environment variables.
+// nil is returned if YONTRACK_URL is not set. lookup is typically os.LookupEnv.
+//
+// The credentials are not resolved.
+func ConfigFromEnv(lookup func(string) (string, bool)) (*Config, error) {
+	url, ok := lookup(EnvURL)
+	if !ok || url == "" {
+		return nil, nil
+	}
+	getenv := func(name string) string {
+		value, _ := lookup(name)
+		return value
+	}
+	cfg := &Config{
+		Name:     getenv(EnvConfigName),
+		URL:      url,
+		Username: getenv(EnvUsername),
+		Password: getenv(EnvPassword),
+		Token:    getenv(EnvToken),
+		// Same defaults as the config create command
+		ConnectionRetry: ConnectionRetry{
+			MaxWaitTimeSec: 2,
+			MaxCount:       5,
+		},
+	}
+	if cfg.Name == "" {
+		cfg.Name = DefaultEnvConfigName
+	}
+	var err error
+	if cfg.ConnectionRetry.MaxCount, err = envInt(lookup, EnvConnRetryCount, cfg.ConnectionRetry.MaxCount); err != nil {
+		return nil, err
+	}
+	if cfg.ConnectionRetry.MaxWaitTimeSec, err = envInt(lookup, EnvConnRetryWait, cfg.ConnectionRetry.MaxWaitTimeSec); err != nil {
+		return nil, err
+	}
+	if cfg.TimeoutSec, err = envInt(lookup, EnvTimeout, cfg.TimeoutSec); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func envInt(lookup func(string) (string, bool), name string, defaultValue int) (int, error) {
+	value, ok := lookup(name)
+	if !ok || value == "" {
+		return defaultValue, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 0 {
+		return 0, newConfigurationError("Invalid value for %s: %s (expected a positive number)", name, value)
+	}
+	return number, nil
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lookupMap returns a lookup function reading the variables from env
+func lookupMap(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	cfg, err := ConfigFromEnv(lookupMap(map[string]string{"YONTRACK_TOKEN": "abc"}))
+	require.NoError(t, err)
+	assert.Nil(t, cfg, "No configuration without any URL")
+
+	cfg, err = ConfigFromEnv(lookupMap(map[string]string{
+		"YONTRACK_URL":   "https://ontrack.example.com",
+		"YONTRACK_TOKEN": "abc",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, &Config{
+		Name:            "env",
+		URL:             "https://ontrack.example.com",
+		Token:           "abc",
+		ConnectionRetry: ConnectionRetry{MaxWaitTimeSec: 2, MaxCount: 5},
+	}, cfg)
+
+	cfg, err = ConfigFromEnv(lookupMap(map[string]string{
+		"YONTRACK_URL":              "https://ontrack.example.com",
+		"YONTRACK_CONFIG_NAME":      "ci",
+		"YONTRACK_USERNAME":         "admin",
+		"YONTRACK_PASSWORD":         "secret",
+		"YONTRACK_CONN_RETRY_COUNT": "0",
+		"YONTRACK_CONN_RETRY_WAIT":  "10",
+		"YONTRACK_TIMEOUT":          "30",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, &Config{
+		Name:            "ci",
+		URL:             "https://ontrack.example.com",
+		Username:        "admin",
+		Password:        "secret",
+		ConnectionRetry: ConnectionRetry{MaxWaitTimeSec: 10, MaxCount: 0},
+		TimeoutSec:      30,
+	}, cfg)
+
+	_, err = ConfigFromEnv(lookupMap(map[string]string{
+		"YONTRACK_URL":     "https://ontrack.example.com",
+		"YONTRACK_TIMEOUT": "ten",
+	}))
+	var configurationError *ConfigurationError
+	require.True(t, errors.As(err, &configurationError))
+	assert.Equal(t, "Invalid value for YONTRACK_TIMEOUT: ten (expected a positive number)", err.Error())
+}
+
+func TestGetSelectedConfiguration_Precedence(t *testing.T) {
+	previousPath, previousContext := ConfigFilePath, ContextName
+	t.Cleanup(func() {
+		ConfigFilePath, ContextName = previousPath, previousContext
+	})
+	ConfigFilePath = filepath.Join(t.TempDir(), "config.yaml")
+	ContextName = ""
+	require.NoError(t, os.WriteFile(ConfigFilePath, []byte(`
+selected: prod
+configurations:
+  - name: prod
+    url: https://prod.example.com
+  - name: staging
+    url: https://staging.example.com
+`), 0600))
+
+	cfg, err := GetSelectedConfiguration()
+	require.NoError(t, err)
+	assert.Equal(t, "prod", cfg.Name)
+
+	t.Setenv("YONTRACK_URL", "https://ci.example.com")
+	t.Setenv("YONTRACK_TOKEN", "env:TEST_ONTRACK_CI_TOKEN")
+	t.Setenv("TEST_ONTRACK_CI_TOKEN", "ci-token")
+	cfg, err = GetSelectedConfiguration()
+	require.NoError(t, err)
+	assert.Equal(t, "env", cfg.Name)
+	assert.Equal(t, "https://ci.example.com", cfg.URL)
+	assert.Equal(t, "ci-token", cfg.Token)
+
+	ContextName = "staging"
+	cfg, err = GetSelectedConfiguration()
+	require.NoError(t, err)
+	assert.Equal(t, "https://staging.example.com", cfg.URL)
+
+	// The selected configuration is not changed
+	root, err := ReadRootConfiguration()
+	require.NoError(t, err)
+	assert.Equal(t, "prod", root.Selected)
+
+	ContextName = "unknown"
+	_, err = GetSelectedConfiguration()
+	assert.EqualError(t, err, "No configuration named unknown")
+}
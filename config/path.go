@@ -0,0 +1,82 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+// EnvConfigPath is the environment variable overriding the path to the configuration file
+const EnvConfigPath = "YONTRACK_CONFIG"
+
+// LocalConfigFileName is the name of the configuration file looked for in the current directory and its parents
+const LocalConfigFileName = ".yontrack-config.yaml"
+
+// Origins of the path to the configuration file
+const (
+	// Set by the --config flag
+	ConfigSourceFlag = "flag"
+	// Set by the YONTRACK_CONFIG environment variable
+	ConfigSourceEnv = "env"
+	// .yontrack-config.yaml file found in the current directory or one of its parents
+	ConfigSourceProject = "project"
+	// yontrack/config.yaml file in the user configuration directory
+	ConfigSourceUser = "user"
+)
+
+// ResolveConfigFilePath returns the path to the configuration file in effect and where it comes from,
+// looking in order at:
+//
+// * the --config flag
+// * the YONTRACK_CONFIG environment variable
+// * a .yontrack-config.yaml file in the current directory or one of its parents
+// * $XDG_CONFIG_HOME/yontrack/config.yaml, $XDG_CONFIG_HOME defaulting to ~/.config
+func ResolveConfigFilePath() (string, string, error) {
+	if ConfigFilePath != "" {
+		path, err := homedir.Expand(ConfigFilePath)
+		return path, ConfigSourceFlag, err
+	}
+	if value := os.Getenv(EnvConfigPath); value != "" {
+		path, err := homedir.Expand(value)
+		return path, ConfigSourceEnv, err
+	}
+	if path, err := findLocalConfigFile(); err != nil {
+		return "", "", err
+	} else if path != "" {
+		return path, ConfigSourceProject, nil
+	}
+	path, err := userConfigFilePath()
+	return path, ConfigSourceUser, err
+}
+
+// findLocalConfigFile looks for the .yontrack-config.yaml file from the current directory up to the root
+func findLocalConfigFile() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for {
+		path := filepath.Join(dir, LocalConfigFileName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+func userConfigFilePath() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := homedir.Dir()
+		if err != nil {
+			return "", newConfigurationError("Cannot locate the configuration file: %s", err)
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "yontrack", "config.yaml"), nil
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveConfigFilePath(t *testing.T) {
+	previous := ConfigFilePath
+	t.Cleanup(func() {
+		ConfigFilePath = previous
+	})
+	ConfigFilePath = ""
+
+	root := t.TempDir()
+	project := filepath.Join(root, "project")
+	work := filepath.Join(project, "sub", "dir")
+	require.NoError(t, os.MkdirAll(work, 0700))
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(work))
+	t.Cleanup(func() {
+		_ = os.Chdir(wd)
+	})
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(root, "xdg"))
+	t.Setenv("YONTRACK_CONFIG", "")
+
+	// User configuration directory
+	path, source, err := ResolveConfigFilePath()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "xdg", "yontrack", "config.yaml"), path)
+	assert.Equal(t, ConfigSourceUser, source)
+
+	// Project file in a parent directory
+	local := filepath.Join(project, ".yontrack-config.yaml")
+	require.NoError(t, os.WriteFile(local, nil, 0600))
+	path, source, err = ResolveConfigFilePath()
+	require.NoError(t, err)
+	assert.Equal(t, ConfigSourceProject, source)
+	// The temporary directory may be a symbolic link
+	expected, err := filepath.EvalSymlinks(local)
+	require.NoError(t, err)
+	actual, err := filepath.EvalSymlinks(path)
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+
+	// Environment
+	t.Setenv("YONTRACK_CONFIG", filepath.Join(root, "env.yaml"))
+	path, source, err = ResolveConfigFilePath()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "env.yaml"), path)
+	assert.Equal(t, ConfigSourceEnv, source)
+
+	// Flag
+	ConfigFilePath = filepath.Join(root, "flag.yaml")
+	path, source, err = ResolveConfigFilePath()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "flag.yaml"), path)
+	assert.Equal(t, ConfigSourceFlag, source)
+}
+
+func TestAddConfiguration_UserConfigDirectory(t *testing.T) {
+	previous := ConfigFilePath
+	t.Cleanup(func() {
+		ConfigFilePath = previous
+	})
+	ConfigFilePath = ""
+	t.Setenv("YONTRACK_CONFIG", "")
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	t.Cleanup(func() {
+		_ = os.Chdir(wd)
+	})
+
+	require.NoError(t, AddConfiguration(Config{Name: "prod", URL: "https://prod.example.com"}, false))
+	_, err = os.Stat(filepath.Join(xdg, "yontrack", "config.yaml"))
+	assert.NoError(t, err, "The yontrack directory is created")
+}
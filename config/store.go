@@ -0,0 +1,168 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// EnvCredentialsKey is the environment variable containing the passphrase of the credential store
+const EnvCredentialsKey = "YONTRACK_CREDENTIALS_KEY"
+
+// Size in bytes of the random salt stored at the beginning of the credential store
+const credentialStoreSaltSize = 16
+
+// CredentialStore is a local file of credentials, encrypted using AES-GCM.
+//
+// The encryption key is derived, using scrypt, from the passphrase set by the YONTRACK_CREDENTIALS_KEY
+// environment variable, which is never stored on disk. Without this variable, the store cannot be used.
+type CredentialStore struct {
+	// Path to the encrypted file
+	Path string
+}
+
+// DefaultCredentialStore returns the store located in the yontrack directory of the user configuration directory
+func DefaultCredentialStore() (*CredentialStore, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("cannot locate the credential store: %w", err)
+	}
+	return &CredentialStore{
+		Path: filepath.Join(dir, "yontrack", "credentials"),
+	}, nil
+}
+
+// Get returns the credential stored under the given name
+func (s *CredentialStore) Get(name string) (string, error) {
+	credentials, _, err := s.read()
+	if err != nil {
+		return "", err
+	}
+	value, ok := credentials[name]
+	if !ok {
+		return "", fmt.Errorf("no credential named %s in the store", name)
+	}
+	return value, nil
+}
+
+// Set stores a credential under the given name, replacing any existing one
+func (s *CredentialStore) Set(name string, value string) error {
+	return s.update(func(credentials map[string]string) bool {
+		credentials[name] = value
+		return true
+	})
+}
+
+// Delete removes a credential from the store, if it exists
+func (s *CredentialStore) Delete(name string) error {
+	return s.update(func(credentials map[string]string) bool {
+		if _, ok := credentials[name]; !ok {
+			return false
+		}
+		delete(credentials, name)
+		return true
+	})
+}
+
+// update changes the credentials while holding the lock of the store, and saves them
+// back if the change function returns true
+func (s *CredentialStore) update(change func(credentials map[string]string) bool) error {
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0700); err != nil {
+		return err
+	}
+	unlock, err := lockFile(s.Path)
+	if err != nil {
+		return fmt.Errorf("cannot lock the credential store: %w", err)
+	}
+	defer unlock()
+
+	credentials, salt, err := s.read()
+	if err != nil {
+		return err
+	}
+	if !change(credentials) {
+		return nil
+	}
+	return s.write(credentials, salt)
+}
+
+// read decrypts the content of the store and returns it with its salt. An empty store
+// is returned if the file does not exist.
+func (s *CredentialStore) read() (map[string]string, []byte, error) {
+	credentials := make(map[string]string)
+	content, err := os.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return credentials, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+	if len(content) < credentialStoreSaltSize {
+		return nil, nil, errors.New("the credential store is corrupted")
+	}
+	salt, content := content[:credentialStoreSaltSize], content[credentialStoreSaltSize:]
+	gcm, err := s.cipher(salt)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(content) < gcm.NonceSize() {
+		return nil, nil, errors.New("the credential store is corrupted")
+	}
+	nonce, encrypted := content[:gcm.NonceSize()], content[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, encrypted, nil)
+	if err != nil {
+		return nil, nil, errors.New("cannot decrypt the credential store (wrong key?)")
+	}
+	if err := json.Unmarshal(plain, &credentials); err != nil {
+		return nil, nil, fmt.Errorf("the credential store is corrupted: %w", err)
+	}
+	return credentials, salt, nil
+}
+
+// write encrypts the credentials and replaces the store atomically. A new salt is
+// generated when none is given.
+func (s *CredentialStore) write(credentials map[string]string, salt []byte) error {
+	plain, err := json.Marshal(credentials)
+	if err != nil {
+		return err
+	}
+	if salt == nil {
+		salt = make([]byte, credentialStoreSaltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return err
+		}
+	}
+	gcm, err := s.cipher(salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	content := append(append([]byte{}, salt...), gcm.Seal(nonce, nonce, plain, nil)...)
+	return writeFileAtomically(s.Path, content)
+}
+
+// cipher derives the 256 bits key from the passphrase and the salt
+func (s *CredentialStore) cipher(salt []byte) (cipher.AEAD, error) {
+	passphrase := os.Getenv(EnvCredentialsKey)
+	if passphrase == "" {
+		return nil, fmt.Errorf("the credential store requires the %s environment variable (or use a credential helper to rely on an OS keychain)", EnvCredentialsKey)
+	}
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
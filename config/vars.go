@@ -6,5 +6,8 @@ var Version = "Snapshot"
 // GraphQL logging flag
 var GraphQLLogging bool = false
 
-// Configuration file path
+// Configuration file path, as set by the --config flag (see ResolveConfigFilePath)
 var ConfigFilePath string
+
+// Name of the configuration to use for the current command instead of the selected one
+var ContextName string
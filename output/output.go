@@ -0,0 +1,240 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/spf13/cobra"
+	yamljson "sigs.k8s.io/yaml"
+)
+
+// Supported output formats
+const (
+	Table    = "table"
+	JSON     = "json"
+	NDJSON   = "ndjson"
+	YAML     = "yaml"
+	Env      = "env"
+	Template = "template"
+)
+
+// Formats lists all the supported output formats
+var Formats = []string{Table, JSON, NDJSON, YAML, Env, Template}
+
+// Variable is a KEY=VALUE pair for the env output
+type Variable struct {
+	Name  string
+	Value string
+}
+
+// Result is what a command wants to print, in all the supported formats
+type Result struct {
+	// Raw value, used by the JSON, YAML and template outputs.
+	// For the NDJSON output, each element of a slice is printed on its own line.
+	Value interface{}
+	// Column headers for the table output
+	Headers []string
+	// Rows for the table output
+	Rows [][]string
+	// Variables for the env output
+	Env []Variable
+	// Default output, used when no format is selected. Nothing is printed if not set.
+	Plain func(w io.Writer) error
+}
+
+// Printer prints results according to the output format selected by the user
+type Printer struct {
+	Format   string
+	Template string
+	Writer   io.Writer
+	// Optional file the env variables are written to, in addition to the output
+	Sink *Sink
+}
+
+// RegisterPersistentFlags registers the global output flags on the root command
+func RegisterPersistentFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP("output", "o", "", "Output format: "+strings.Join(Formats, ", ")+". Defaults to the command own output.")
+	cmd.PersistentFlags().String("template", "", "Go template used to render the output (implies --output template). Sprig functions are available.")
+}
+
+// FromCommand creates a printer based on the output flags of the command
+func FromCommand(cmd *cobra.Command) (*Printer, error) {
+	format, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := cmd.Flags().GetString("template")
+	if err != nil {
+		return nil, err
+	}
+	printer, err := NewPrinter(format, tmpl, os.Stdout)
+	if err != nil {
+		return nil, err
+	}
+	if printer.Sink, err = SinkFromCommand(cmd); err != nil {
+		return nil, err
+	}
+	return printer, nil
+}
+
+// NewPrinter creates a printer for the given format, checking it's supported
+func NewPrinter(format string, tmpl string, writer io.Writer) (*Printer, error) {
+	if format == "" && tmpl != "" {
+		format = Template
+	}
+	if format != "" && !isSupported(format) {
+		return nil, fmt.Errorf("unsupported output format %s (expected one of: %s)", format, strings.Join(Formats, ", "))
+	}
+	if format == Template && tmpl == "" {
+		return nil, fmt.Errorf("the --template flag is required for the template output")
+	}
+	return &Printer{
+		Format:   format,
+		Template: tmpl,
+		Writer:   writer,
+	}, nil
+}
+
+// IsSet checks if an output format has been selected
+func (p *Printer) IsSet() bool {
+	return p.Format != ""
+}
+
+// Print prints the result using the selected format, and writes its env variables to the sink if any
+func (p *Printer) Print(result *Result) error {
+	if p.Sink == nil {
+		return p.print(result)
+	}
+	if result.Env == nil {
+		return fmt.Errorf("output file not supported by this command")
+	}
+	if err := p.print(result); err != nil {
+		return err
+	}
+	return p.Sink.Write(result.Env)
+}
+
+func (p *Printer) print(result *Result) error {
+	switch p.Format {
+	case "":
+		if result.Plain != nil {
+			return result.Plain(p.Writer)
+		}
+		return nil
+	case Table:
+		return p.printTable(result)
+	case JSON:
+		return p.printJSON(result.Value)
+	case NDJSON:
+		return p.printNDJSON(result.Value)
+	case YAML:
+		return p.printYAML(result.Value)
+	case Env:
+		return p.printEnv(result)
+	case Template:
+		return p.printTemplate(result.Value)
+	default:
+		return fmt.Errorf("unsupported output format %s", p.Format)
+	}
+}
+
+func (p *Printer) printTable(result *Result) error {
+	if result.Headers == nil {
+		return fmt.Errorf("table output is not supported by this command")
+	}
+	w := tabwriter.NewWriter(p.Writer, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, strings.Join(result.Headers, "\t"))
+	for _, row := range result.Rows {
+		_, _ = fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+func (p *Printer) printJSON(value interface{}) error {
+	jsonBytes, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal output to JSON: %w", err)
+	}
+	_, err = fmt.Fprintf(p.Writer, "%s\n", jsonBytes)
+	return err
+}
+
+func (p *Printer) printNDJSON(value interface{}) error {
+	items := reflect.ValueOf(value)
+	if items.Kind() != reflect.Slice && items.Kind() != reflect.Array {
+		return p.printJSONLine(value)
+	}
+	for i := 0; i < items.Len(); i++ {
+		if err := p.printJSONLine(items.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Printer) printJSONLine(value interface{}) error {
+	jsonBytes, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal output to JSON: %w", err)
+	}
+	_, err = fmt.Fprintf(p.Writer, "%s\n", jsonBytes)
+	return err
+}
+
+func (p *Printer) printYAML(value interface{}) error {
+	yamlBytes, err := yamljson.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal output to YAML: %w", err)
+	}
+	_, err = p.Writer.Write(yamlBytes)
+	return err
+}
+
+func (p *Printer) printEnv(result *Result) error {
+	if result.Env == nil {
+		return fmt.Errorf("env output is not supported by this command")
+	}
+	for _, variable := range result.Env {
+		if _, err := fmt.Fprintf(p.Writer, "export %s=%s\n", variable.Name, variable.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Printer) printTemplate(value interface{}) error {
+	tmpl, err := template.New("output").Funcs(sprig.TxtFuncMap()).Parse(p.Template)
+	if err != nil {
+		return fmt.Errorf("error parsing output template: %w", err)
+	}
+	// Templates work on the JSON representation so that field names are the same
+	// as in the JSON and YAML outputs
+	jsonBytes, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal output to JSON: %w", err)
+	}
+	var data interface{}
+	if err := json.Unmarshal(jsonBytes, &data); err != nil {
+		return err
+	}
+	if err := tmpl.Execute(p.Writer, data); err != nil {
+		return fmt.Errorf("error executing output template: %w", err)
+	}
+	return nil
+}
+
+func isSupported(format string) bool {
+	for _, item := range Formats {
+		if item == format {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type item struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func testResult() *Result {
+	return &Result{
+		Value:   []item{{ID: 1, Name: "one"}, {ID: 2, Name: "two"}},
+		Headers: []string{"ID", "NAME"},
+		Rows:    [][]string{{"1", "one"}, {"2", "two"}},
+		Env:     []Variable{{Name: "FIRST", Value: "one"}},
+		Plain: func(w io.Writer) error {
+			_, err := fmt.Fprintln(w, "plain")
+			return err
+		},
+	}
+}
+
+func render(t *testing.T, format string, tmpl string, result *Result) string {
+	var buffer bytes.Buffer
+	printer, err := NewPrinter(format, tmpl, &buffer)
+	require.NoError(t, err)
+	require.NoError(t, printer.Print(result))
+	return buffer.String()
+}
+
+func TestPrinter_Formats(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		template string
+		expected string
+	}{
+		{"default", "", "", "plain\n"},
+		{"table", Table, "", "ID  NAME\n1   one\n2   two\n"},
+		{"json", JSON, "", "[\n  {\n    \"id\": 1,\n    \"name\": \"one\"\n  },\n  {\n    \"id\": 2,\n    \"name\": \"two\"\n  }\n]\n"},
+		{"ndjson", NDJSON, "", "{\"id\":1,\"name\":\"one\"}\n{\"id\":2,\"name\":\"two\"}\n"},
+		{"yaml", YAML, "", "- id: 1\n  name: one\n- id: 2\n  name: two\n"},
+		{"env", Env, "", "export FIRST=one\n"},
+		{"template", Template, "{{range .}}{{.name | upper}};{{end}}", "ONE;TWO;"},
+		{"template implied", "", "{{len .}}", "2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, render(t, tt.format, tt.template, testResult()))
+		})
+	}
+}
+
+func TestPrinter_NoPlainOutput(t *testing.T) {
+	assert.Equal(t, "", render(t, "", "", &Result{Value: "value"}))
+}
+
+func TestPrinter_UnsupportedByCommand(t *testing.T) {
+	var buffer bytes.Buffer
+	for _, format := range []string{Table, Env} {
+		printer, err := NewPrinter(format, "", &buffer)
+		require.NoError(t, err)
+		assert.Error(t, printer.Print(&Result{Value: "value"}))
+	}
+}
+
+func TestNewPrinter_Errors(t *testing.T) {
+	_, err := NewPrinter("xml", "", &bytes.Buffer{})
+	assert.EqualError(t, err, "unsupported output format xml (expected one of: table, json, ndjson, yaml, env, template)")
+
+	_, err = NewPrinter(Template, "", &bytes.Buffer{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,176 @@
+package output
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Supported formats for the output files
+const (
+	GitHubOutput = "github-output"
+	GitHubEnv    = "github-env"
+	DotEnv       = "dotenv"
+	Properties   = "properties"
+)
+
+// SinkFormats lists all the supported formats for the output files
+var SinkFormats = []string{GitHubOutput, GitHubEnv, DotEnv, Properties}
+
+// Sink writes the env variables of a result to a file, in addition to the output of the command
+type Sink struct {
+	Format string
+	Path   string
+}
+
+// RegisterSinkFlags registers the --output-file and --output-format flags on a command
+// whose results have env variables
+func RegisterSinkFlags(cmd *cobra.Command) {
+	cmd.Flags().String("output-file", "", "File to write the YONTRACK_* variables to, in addition to the output (default: $GITHUB_OUTPUT or $GITHUB_ENV for the GitHub formats)")
+	cmd.Flags().String("output-format", "", "Format of the output file: "+strings.Join(SinkFormats, ", ")+" (default: dotenv)")
+}
+
+// SinkFromCommand creates the sink selected by the --output-file and --output-format flags.
+// nil is returned if the command does not support these flags or if they are not set.
+func SinkFromCommand(cmd *cobra.Command) (*Sink, error) {
+	if cmd.Flags().Lookup("output-file") == nil {
+		return nil, nil
+	}
+	path, err := cmd.Flags().GetString("output-file")
+	if err != nil {
+		return nil, err
+	}
+	format, err := cmd.Flags().GetString("output-format")
+	if err != nil {
+		return nil, err
+	}
+	return NewSink(format, path, os.Getenv)
+}
+
+// NewSink creates a sink, checking its format and defaulting its path for the GitHub formats.
+// nil is returned if neither the format nor the path is set.
+func NewSink(format string, path string, getenv func(string) string) (*Sink, error) {
+	if format == "" && path == "" {
+		return nil, nil
+	}
+	if format == "" {
+		format = DotEnv
+	}
+	if path == "" {
+		switch format {
+		case GitHubOutput:
+			path = getenv("GITHUB_OUTPUT")
+		case GitHubEnv:
+			path = getenv("GITHUB_ENV")
+		}
+	}
+	if !isSinkFormat(format) {
+		return nil, fmt.Errorf("unsupported output file format %s (expected one of: %s)", format, strings.Join(SinkFormats, ", "))
+	}
+	if path == "" {
+		return nil, fmt.Errorf("the --output-file flag is required for the %s output format", format)
+	}
+	return &Sink{Format: format, Path: path}, nil
+}
+
+// Write writes the variables to the file. The GitHub files are appended to, the
+// other ones are replaced.
+func (s *Sink) Write(variables []Variable) error {
+	var content strings.Builder
+	for _, variable := range variables {
+		line, err := s.format(variable)
+		if err != nil {
+			return err
+		}
+		content.WriteString(line)
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if s.Format == GitHubOutput || s.Format == GitHubEnv {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+	file, err := os.OpenFile(s.Path, flags, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open output file: %w", err)
+	}
+	if _, err := file.WriteString(content.String()); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	return file.Close()
+}
+
+func (s *Sink) format(variable Variable) (string, error) {
+	switch s.Format {
+	case GitHubOutput, GitHubEnv:
+		if !strings.ContainsAny(variable.Value, "\r\n") {
+			return fmt.Sprintf("%s=%s\n", variable.Name, variable.Value), nil
+		}
+		delimiter, err := githubDelimiter()
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s<<%s\n%s\n%s\n", variable.Name, delimiter, variable.Value, delimiter), nil
+	case DotEnv:
+		if strings.ContainsAny(variable.Value, "\r\n") {
+			return "", fmt.Errorf("multiline value for %s is not supported by the dotenv format", variable.Name)
+		}
+		return fmt.Sprintf("%s=%s\n", variable.Name, variable.Value), nil
+	case Properties:
+		return fmt.Sprintf("%s=%s\n", escapeProperty(variable.Name, true), escapeProperty(variable.Value, false)), nil
+	default:
+		return "", fmt.Errorf("unsupported output file format %s", s.Format)
+	}
+}
+
+// githubDelimiter returns a random delimiter for the multiline values of the GitHub files
+func githubDelimiter() (string, error) {
+	bytes := make([]byte, 8)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return "ghadelimiter_" + hex.EncodeToString(bytes), nil
+}
+
+// escapeProperty escapes a key or a value for a Java properties file
+func escapeProperty(text string, key bool) string {
+	var b strings.Builder
+	for index, c := range text {
+		switch c {
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '=', ':', '#', '!':
+			if key {
+				b.WriteRune('\\')
+			}
+			b.WriteRune(c)
+		case ' ':
+			if key || index == 0 {
+				b.WriteRune('\\')
+			}
+			b.WriteRune(c)
+		default:
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+func isSinkFormat(format string) bool {
+	for _, item := range SinkFormats {
+		if item == format {
+			return true
+		}
+	}
+	return false
+}
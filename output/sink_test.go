@@ -0,0 +1,117 @@
+package output
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// getenvMap returns a getenv function reading the variables from env
+func getenvMap(env map[string]string) func(string) string {
+	return func(name string) string {
+		return env[name]
+	}
+}
+
+func TestNewSink(t *testing.T) {
+	env := getenvMap(map[string]string{
+		"GITHUB_OUTPUT": "/runner/output",
+		"GITHUB_ENV":    "/runner/env",
+	})
+	tests := []struct {
+		format   string
+		path     string
+		expected *Sink
+	}{
+		{"", "", nil},
+		{"", "build.env", &Sink{Format: DotEnv, Path: "build.env"}},
+		{GitHubOutput, "", &Sink{Format: GitHubOutput, Path: "/runner/output"}},
+		{GitHubEnv, "", &Sink{Format: GitHubEnv, Path: "/runner/env"}},
+		{GitHubEnv, "custom", &Sink{Format: GitHubEnv, Path: "custom"}},
+		{Properties, "build.properties", &Sink{Format: Properties, Path: "build.properties"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format+":"+tt.path, func(t *testing.T) {
+			sink, err := NewSink(tt.format, tt.path, env)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, sink)
+		})
+	}
+}
+
+func TestNewSink_Errors(t *testing.T) {
+	_, err := NewSink("xml", "file.xml", getenvMap(nil))
+	assert.EqualError(t, err, "unsupported output file format xml (expected one of: github-output, github-env, dotenv, properties)")
+	_, err = NewSink(GitHubOutput, "", getenvMap(nil))
+	assert.EqualError(t, err, "the --output-file flag is required for the github-output output format")
+	_, err = NewSink(DotEnv, "", getenvMap(nil))
+	assert.Error(t, err)
+}
+
+func TestSink_Write(t *testing.T) {
+	variables := []Variable{
+		{Name: "YONTRACK_PROJECT_NAME", Value: "my project"},
+		{Name: "YONTRACK_BRANCH_NAME", Value: "release/5.0"},
+	}
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{GitHubOutput, "EXISTING=1\nYONTRACK_PROJECT_NAME=my project\nYONTRACK_BRANCH_NAME=release/5.0\n"},
+		{GitHubEnv, "EXISTING=1\nYONTRACK_PROJECT_NAME=my project\nYONTRACK_BRANCH_NAME=release/5.0\n"},
+		{DotEnv, "YONTRACK_PROJECT_NAME=my project\nYONTRACK_BRANCH_NAME=release/5.0\n"},
+		{Properties, "YONTRACK_PROJECT_NAME=my project\nYONTRACK_BRANCH_NAME=release/5.0\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "output")
+			require.NoError(t, os.WriteFile(path, []byte("EXISTING=1\n"), 0644))
+
+			sink := &Sink{Format: tt.format, Path: path}
+			require.NoError(t, sink.Write(variables))
+			content, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(content))
+		})
+	}
+}
+
+func TestSink_WriteMultiline(t *testing.T) {
+	variables := []Variable{{Name: "NOTES", Value: "first\nsecond"}}
+	path := filepath.Join(t.TempDir(), "output")
+
+	require.NoError(t, (&Sink{Format: GitHubOutput, Path: path}).Write(variables))
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^NOTES<<(ghadelimiter_[0-9a-f]+)\nfirst\nsecond\n(ghadelimiter_[0-9a-f]+)\n$`), string(content))
+
+	require.NoError(t, (&Sink{Format: Properties, Path: path}).Write(append(variables, Variable{Name: "KEY:1", Value: " C:\\tmp=1"})))
+	content, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "NOTES=first\\nsecond\nKEY\\:1=\\ C:\\\\tmp=1\n", string(content))
+
+	assert.Error(t, (&Sink{Format: DotEnv, Path: path}).Write(variables))
+}
+
+func TestPrinter_Sink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "build.env")
+	var buffer bytes.Buffer
+	printer, err := NewPrinter("", "", &buffer)
+	require.NoError(t, err)
+	printer.Sink = &Sink{Format: DotEnv, Path: path}
+
+	require.NoError(t, printer.Print(testResult()))
+	assert.Equal(t, "plain\n", buffer.String())
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "FIRST=one\n", string(content))
+
+	result := testResult()
+	result.Env = nil
+	assert.EqualError(t, printer.Print(result), "output file not supported by this command")
+}
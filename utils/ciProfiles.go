@@ -0,0 +1,115 @@
+package utils
+
+import "strings"
+
+// CIProfile lists the environment variables exposed by a CI engine which are
+// relevant for Ontrack. Secrets and credentials are never part of a profile.
+type CIProfile struct {
+	// ID of the CI engine, as used by the --ci flag
+	ID string
+	// Display name of the CI engine
+	Name string
+	// Variable whose presence identifies the CI engine
+	DetectVariable string
+	// Variables to pass to Ontrack
+	Variables []string
+}
+
+// CIProfiles are the built-in CI profiles
+var CIProfiles = []CIProfile{
+	{
+		ID:             "jenkins",
+		Name:           "Jenkins",
+		DetectVariable: "JENKINS_URL",
+		Variables: []string{
+			"JENKINS_URL", "JOB_NAME", "JOB_URL", "BUILD_NUMBER", "BUILD_URL",
+			"GIT_URL", "GIT_BRANCH", "GIT_COMMIT", "BRANCH_NAME", "TAG_NAME",
+			"CHANGE_ID", "CHANGE_BRANCH", "CHANGE_TARGET", "CHANGE_URL",
+		},
+	},
+	{
+		ID:             "github",
+		Name:           "GitHub Actions",
+		DetectVariable: "GITHUB_ACTIONS",
+		Variables: []string{
+			"GITHUB_ACTIONS", "GITHUB_SERVER_URL", "GITHUB_REPOSITORY", "GITHUB_REPOSITORY_OWNER",
+			"GITHUB_REF", "GITHUB_REF_NAME", "GITHUB_REF_TYPE", "GITHUB_HEAD_REF", "GITHUB_BASE_REF",
+			"GITHUB_SHA", "GITHUB_WORKFLOW", "GITHUB_RUN_ID", "GITHUB_RUN_NUMBER", "GITHUB_RUN_ATTEMPT",
+			"GITHUB_EVENT_NAME", "GITHUB_JOB", "GITHUB_ACTOR",
+		},
+	},
+	{
+		ID:             "gitlab",
+		Name:           "GitLab CI",
+		DetectVariable: "GITLAB_CI",
+		Variables: []string{
+			"GITLAB_CI", "CI_SERVER_URL", "CI_PROJECT_ID", "CI_PROJECT_PATH", "CI_PROJECT_URL",
+			"CI_COMMIT_BRANCH", "CI_COMMIT_REF_NAME", "CI_COMMIT_TAG", "CI_COMMIT_SHA", "CI_DEFAULT_BRANCH",
+			"CI_MERGE_REQUEST_IID", "CI_MERGE_REQUEST_SOURCE_BRANCH_NAME", "CI_MERGE_REQUEST_TARGET_BRANCH_NAME",
+			"CI_PIPELINE_ID", "CI_PIPELINE_IID", "CI_PIPELINE_URL", "CI_JOB_ID", "CI_JOB_NAME", "CI_JOB_URL",
+		},
+	},
+	{
+		ID:             "bitbucket",
+		Name:           "Bitbucket Pipelines",
+		DetectVariable: "BITBUCKET_BUILD_NUMBER",
+		Variables: []string{
+			"BITBUCKET_BUILD_NUMBER", "BITBUCKET_WORKSPACE", "BITBUCKET_REPO_SLUG", "BITBUCKET_REPO_FULL_NAME",
+			"BITBUCKET_GIT_HTTP_ORIGIN", "BITBUCKET_BRANCH", "BITBUCKET_TAG", "BITBUCKET_COMMIT",
+			"BITBUCKET_PR_ID", "BITBUCKET_PR_DESTINATION_BRANCH", "BITBUCKET_PIPELINE_UUID", "BITBUCKET_STEP_UUID",
+		},
+	},
+	{
+		ID:             "azure",
+		Name:           "Azure Pipelines",
+		DetectVariable: "TF_BUILD",
+		Variables: []string{
+			"TF_BUILD", "SYSTEM_COLLECTIONURI", "SYSTEM_TEAMPROJECT", "SYSTEM_DEFINITIONNAME",
+			"BUILD_BUILDID", "BUILD_BUILDNUMBER", "BUILD_REPOSITORY_URI", "BUILD_REPOSITORY_NAME",
+			"BUILD_SOURCEBRANCH", "BUILD_SOURCEBRANCHNAME", "BUILD_SOURCEVERSION",
+			"SYSTEM_PULLREQUEST_PULLREQUESTID", "SYSTEM_PULLREQUEST_SOURCEBRANCH", "SYSTEM_PULLREQUEST_TARGETBRANCH",
+		},
+	},
+	{
+		ID:             "circleci",
+		Name:           "CircleCI",
+		DetectVariable: "CIRCLECI",
+		Variables: []string{
+			"CIRCLECI", "CIRCLE_PROJECT_USERNAME", "CIRCLE_PROJECT_REPONAME", "CIRCLE_REPOSITORY_URL",
+			"CIRCLE_BRANCH", "CIRCLE_TAG", "CIRCLE_SHA1", "CIRCLE_BUILD_NUM", "CIRCLE_BUILD_URL",
+			"CIRCLE_WORKFLOW_ID", "CIRCLE_JOB", "CIRCLE_PULL_REQUEST",
+		},
+	},
+}
+
+// GetCIProfile returns the built-in profile with the given ID, or nil if there is none
+func GetCIProfile(id string) *CIProfile {
+	for index := range CIProfiles {
+		if strings.EqualFold(CIProfiles[index].ID, id) {
+			return &CIProfiles[index]
+		}
+	}
+	return nil
+}
+
+// DetectCIProfile returns the profile of the CI engine the CLI is running in, or nil if
+// no engine can be detected. lookup is typically os.LookupEnv.
+func DetectCIProfile(lookup func(string) (string, bool)) *CIProfile {
+	for index := range CIProfiles {
+		if value, ok := lookup(CIProfiles[index].DetectVariable); ok && value != "" {
+			return &CIProfiles[index]
+		}
+	}
+	return nil
+}
+
+// Collect returns the variables of the profile which are set
+func (p *CIProfile) Collect(lookup func(string) (string, bool)) map[string]string {
+	env := make(map[string]string)
+	for _, name := range p.Variables {
+		if value, ok := lookup(name); ok {
+			env[name] = value
+		}
+	}
+	return env
+}
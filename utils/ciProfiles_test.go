@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lookupMap returns a lookup function reading the variables from env
+func lookupMap(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+}
+
+func TestGetCIProfile(t *testing.T) {
+	profile := GetCIProfile("GitHub")
+	require.NotNil(t, profile)
+	assert.Equal(t, "github", profile.ID)
+	assert.Nil(t, GetCIProfile("unknown"))
+}
+
+func TestDetectCIProfile(t *testing.T) {
+	tests := []struct {
+		env      map[string]string
+		expected string
+	}{
+		{map[string]string{"JENKINS_URL": "https://jenkins"}, "jenkins"},
+		{map[string]string{"GITHUB_ACTIONS": "true"}, "github"},
+		{map[string]string{"GITLAB_CI": "true"}, "gitlab"},
+		{map[string]string{"BITBUCKET_BUILD_NUMBER": "12"}, "bitbucket"},
+		{map[string]string{"TF_BUILD": "True"}, "azure"},
+		{map[string]string{"CIRCLECI": "true"}, "circleci"},
+		{map[string]string{"GITHUB_ACTIONS": ""}, ""},
+		{map[string]string{}, ""},
+	}
+	for _, tt := range tests {
+		profile := DetectCIProfile(lookupMap(tt.env))
+		if tt.expected == "" {
+			assert.Nil(t, profile)
+		} else {
+			require.NotNil(t, profile)
+			assert.Equal(t, tt.expected, profile.ID)
+		}
+	}
+}
+
+func TestCIProfile_Collect(t *testing.T) {
+	env := map[string]string{
+		"GITHUB_ACTIONS":    "true",
+		"GITHUB_REPOSITORY": "nemerosa/ontrack",
+		"GITHUB_REF_NAME":   "release/5.0",
+		"GITHUB_TOKEN":      "ghp_secret",
+		"HOME":              "/home/runner",
+	}
+	assert.Equal(t, map[string]string{
+		"GITHUB_ACTIONS":    "true",
+		"GITHUB_REPOSITORY": "nemerosa/ontrack",
+		"GITHUB_REF_NAME":   "release/5.0",
+	}, GetCIProfile("github").Collect(lookupMap(env)))
+}
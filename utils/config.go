@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -11,7 +12,43 @@ import (
 	"gopkg.in/yaml.v2"
 )
 
+// ExpandOptions customizes the expansion of a configuration
+type ExpandOptions struct {
+	// Path of the file being expanded, used to detect the include cycles. Optional.
+	Path string
+	// Directory containing the copies of the remote includes (http:// and https:// references).
+	// See IncludeCachePath for the layout of this directory.
+	CacheDir string
+}
+
+// ExpandConfig expands the @path references and the extends directives of a YAML configuration
 func ExpandConfig(initial string) (string, error) {
+	return ExpandConfigWithOptions(initial, ExpandOptions{CacheDir: DefaultIncludeCacheDir()})
+}
+
+// ExpandConfigWithOptions expands the @path references and the extends directives of a YAML configuration.
+//
+// A string like @path is replaced by the content of the YAML file at path.
+//
+// A document (the configuration or an included file) with an extends key at its root, or any map
+// with an @extends key, is deep-merged on top of the files it extends (a path or a list of paths):
+// maps are merged and the other values, including lists, are replaced. A @merge key in a map sets the
+// strategy for some keys of this map and of its nested maps: append (lists only), replace or merge
+// (maps only). A @merge in a nested map takes priority over the ones of the enclosing maps:
+//
+//	extends: ../base/ci.yaml
+//	configuration:
+//	  defaults:
+//	    branch:
+//	      "@merge":
+//	        validations: append
+//	      validations:
+//	        - extra-tests
+//
+// Paths are relative to the including file. http:// and https:// references are read from the
+// cache directory. Cycles between includes are reported as errors, as well as the @merge keys which
+// have no effect, because their map is not merged on top of another one.
+func ExpandConfigWithOptions(initial string, options ExpandOptions) (string, error) {
 	// Parse the initial YAML string
 	var data interface{}
 	if err := yaml.Unmarshal([]byte(initial), &data); err != nil {
@@ -19,10 +56,24 @@ func ExpandConfig(initial string) (string, error) {
 	}
 
 	// Expand all @path references
-	expanded, err := expandNode(data, "")
+	e := &expander{cacheDir: options.CacheDir}
+	if options.Path != "" {
+		path, err := filepath.Abs(options.Path)
+		if err != nil {
+			return "", err
+		}
+		e.stack = []string{path}
+	}
+	expanded, err := e.expandNode(data, "", true)
 	if err != nil {
 		return "", err
 	}
+	if path, found := findMergeDirective(expanded, ""); found {
+		if path == "" {
+			path = "the root"
+		}
+		return "", fmt.Errorf("@merge at %s has no effect: its map is not merged on top of an extended one", path)
+	}
 
 	// Marshal back to YAML string
 	result, err := yaml.Marshal(expanded)
@@ -33,53 +84,116 @@ func ExpandConfig(initial string) (string, error) {
 	return string(result), nil
 }
 
-// expandNode recursively processes a YAML node and expands @path references
-func expandNode(node interface{}, baseDir string) (interface{}, error) {
+// expander keeps track of the includes being expanded
+type expander struct {
+	cacheDir string
+	// Includes being expanded, from the outermost one
+	stack []string
+}
+
+// include reads, parses and expands an included file
+func (e *expander) include(reference string, baseDir string) (interface{}, error) {
+	location, err := resolveInclude(reference, baseDir)
+	if err != nil {
+		return nil, err
+	}
+	for index, current := range e.stack {
+		if current == location {
+			cycle := append(append([]string{}, e.stack[index:]...), location)
+			return nil, fmt.Errorf("include cycle detected: %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	// Read the file at path
+	path := location
+	if isRemoteInclude(location) {
+		if path, err = IncludeCachePath(e.cacheDir, location); err != nil {
+			return nil, err
+		}
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if isRemoteInclude(location) && os.IsNotExist(err) {
+			return nil, fmt.Errorf("remote include %s not found in the cache at %s", location, path)
+		}
+		return nil, fmt.Errorf("failed to read file %s: %w", path, err)
+	}
+
+	// Parse the YAML content
+	var parsedData interface{}
+	if err := yaml.Unmarshal(content, &parsedData); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML from %s: %w", path, err)
+	}
+
+	// Recursively expand the parsed data (using the location of the current file as base)
+	e.stack = append(e.stack, location)
+	defer func() {
+		e.stack = e.stack[:len(e.stack)-1]
+	}()
+	return e.expandNode(parsedData, includeDir(location), true)
+}
+
+// Directives of the configuration maps
+const (
+	// Extends some files, at the root of a document only
+	extendsKey = "extends"
+	// Extends some files, in any map
+	extendsDirective = "@extends"
+	// Strategies to merge the keys of a map
+	mergeDirective = "@merge"
+)
+
+// mergeStrategies are the strategies set by a @merge key, kept in the expanded maps until
+// they are merged on top of an extended one
+type mergeStrategies map[string]string
+
+// expandNode recursively processes a YAML node and expands @path references and extends directives.
+// root is true for the root node of a document.
+func (e *expander) expandNode(node interface{}, baseDir string, root bool) (interface{}, error) {
 	switch v := node.(type) {
 	case string:
 		// Check if the string looks like @path
 		if strings.HasPrefix(v, "@") {
-			path := strings.TrimPrefix(v, "@")
-			// Resolve path relative to baseDir if provided
-			if baseDir != "" && !filepath.IsAbs(path) {
-				path = filepath.Join(baseDir, path)
-			}
-
-			// Read the file at path
-			content, err := os.ReadFile(path)
-			if err != nil {
-				return nil, fmt.Errorf("failed to read file %s: %w", path, err)
-			}
-
-			// Parse the YAML content
-			var parsedData interface{}
-			if err := yaml.Unmarshal(content, &parsedData); err != nil {
-				return nil, fmt.Errorf("failed to parse YAML from %s: %w", path, err)
-			}
-
-			// Recursively expand the parsed data (using the directory of the current file as base)
-			newBaseDir := filepath.Dir(path)
-			return expandNode(parsedData, newBaseDir)
+			return e.include(strings.TrimPrefix(v, "@"), baseDir)
 		}
 		return v, nil
 
 	case map[interface{}]interface{}:
 		// Process each key-value pair in the map
 		result := make(map[interface{}]interface{})
+		var extends interface{}
 		for key, value := range v {
-			expandedValue, err := expandNode(value, baseDir)
+			switch {
+			case key == extendsDirective || (root && key == extendsKey):
+				if extends != nil {
+					return nil, fmt.Errorf("extends and @extends cannot be used together")
+				}
+				extends = value
+				continue
+			case key == mergeDirective:
+				strategies, err := parseMergeStrategies(value)
+				if err != nil {
+					return nil, err
+				}
+				result[key] = strategies
+				continue
+			}
+			expandedValue, err := e.expandNode(value, baseDir, false)
 			if err != nil {
 				return nil, err
 			}
 			result[key] = expandedValue
 		}
-		return result, nil
+		if extends == nil {
+			return result, nil
+		}
+		return e.extend(extends, result, baseDir)
 
 	case []interface{}:
 		// Process each element in the array
 		result := make([]interface{}, len(v))
 		for i, elem := range v {
-			expandedElem, err := expandNode(elem, baseDir)
+			expandedElem, err := e.expandNode(elem, baseDir, false)
 			if err != nil {
 				return nil, err
 			}
@@ -93,6 +207,210 @@ func expandNode(node interface{}, baseDir string) (interface{}, error) {
 	}
 }
 
+// extend merges an expanded map on top of the files its extends directive refers to
+func (e *expander) extend(extends interface{}, expanded map[interface{}]interface{}, baseDir string) (interface{}, error) {
+	var references []string
+	switch v := extends.(type) {
+	case string:
+		references = []string{v}
+	case []interface{}:
+		for _, item := range v {
+			reference, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("extends must be a path or a list of paths")
+			}
+			references = append(references, reference)
+		}
+	default:
+		return nil, fmt.Errorf("extends must be a path or a list of paths")
+	}
+
+	var base interface{} = map[interface{}]interface{}{}
+	for index, reference := range references {
+		parent, err := e.include(strings.TrimPrefix(reference, "@"), baseDir)
+		if err != nil {
+			return nil, err
+		}
+		// The first file is the base for the next ones
+		if index == 0 {
+			base = parent
+		} else if base, err = mergeNodes(base, parent, nil); err != nil {
+			return nil, fmt.Errorf("failed to extend %s: %w", reference, err)
+		}
+	}
+	return mergeNodes(base, expanded, nil)
+}
+
+// Strategies to merge a value on top of another one
+const (
+	mergeStrategyMerge   = "merge"
+	mergeStrategyAppend  = "append"
+	mergeStrategyReplace = "replace"
+)
+
+func parseMergeStrategies(node interface{}) (mergeStrategies, error) {
+	items, ok := node.(map[interface{}]interface{})
+	if !ok {
+		return nil, fmt.Errorf("@merge must be a map of keys to merge strategies")
+	}
+	strategies := make(mergeStrategies)
+	for key, value := range items {
+		strategy := fmt.Sprint(value)
+		if strategy != mergeStrategyMerge && strategy != mergeStrategyAppend && strategy != mergeStrategyReplace {
+			return nil, fmt.Errorf("invalid @merge strategy %s for %v (expected merge, append or replace)", strategy, key)
+		}
+		strategies[fmt.Sprint(key)] = strategy
+	}
+	return strategies, nil
+}
+
+// mergeNodes deep-merges the override on top of the base. Maps are merged, the other values are
+// replaced unless a strategy is given for their key, either by the @merge key of the override map
+// or by the ones of the enclosing maps (inherited).
+func mergeNodes(base interface{}, override interface{}, inherited mergeStrategies) (interface{}, error) {
+	baseMap, ok := base.(map[interface{}]interface{})
+	overrideMap, ok2 := override.(map[interface{}]interface{})
+	if !ok || !ok2 {
+		return override, nil
+	}
+	strategies := inherited
+	if own, ok := overrideMap[mergeDirective].(mergeStrategies); ok {
+		strategies = make(mergeStrategies, len(inherited)+len(own))
+		for key, strategy := range inherited {
+			strategies[key] = strategy
+		}
+		for key, strategy := range own {
+			strategies[key] = strategy
+		}
+	}
+	result := make(map[interface{}]interface{}, len(baseMap)+len(overrideMap))
+	for key, value := range baseMap {
+		result[key] = value
+	}
+	for key, value := range overrideMap {
+		if key == mergeDirective {
+			continue
+		}
+		existing, exists := result[key]
+		if !exists {
+			result[key] = value
+			continue
+		}
+		switch strategies[fmt.Sprint(key)] {
+		case mergeStrategyAppend:
+			existingList, ok := existing.([]interface{})
+			list, ok2 := value.([]interface{})
+			if !ok || !ok2 {
+				return nil, fmt.Errorf("cannot append %v: both values must be lists", key)
+			}
+			result[key] = append(append([]interface{}{}, existingList...), list...)
+		case mergeStrategyReplace:
+			result[key] = value
+		default:
+			merged, err := mergeNodes(existing, value, strategies)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = merged
+		}
+	}
+	return result, nil
+}
+
+// findMergeDirective returns the path of a @merge key which has not been used by any merge
+func findMergeDirective(node interface{}, path string) (string, bool) {
+	switch v := node.(type) {
+	case map[interface{}]interface{}:
+		if _, ok := v[mergeDirective]; ok {
+			return path, true
+		}
+		for key, value := range v {
+			if found, ok := findMergeDirective(value, joinConfigPath(path, fmt.Sprint(key))); ok {
+				return found, true
+			}
+		}
+	case []interface{}:
+		for index, value := range v {
+			if found, ok := findMergeDirective(value, fmt.Sprintf("%s[%d]", path, index)); ok {
+				return found, true
+			}
+		}
+	}
+	return "", false
+}
+
+func joinConfigPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func isRemoteInclude(reference string) bool {
+	return strings.HasPrefix(reference, "http://") || strings.HasPrefix(reference, "https://")
+}
+
+// resolveInclude returns the absolute path or the URL of an include
+func resolveInclude(reference string, baseDir string) (string, error) {
+	if isRemoteInclude(reference) {
+		return reference, nil
+	}
+	if isRemoteInclude(baseDir) {
+		base, err := url.Parse(baseDir)
+		if err != nil {
+			return "", err
+		}
+		relative, err := url.Parse(reference)
+		if err != nil {
+			return "", err
+		}
+		return base.ResolveReference(relative).String(), nil
+	}
+	// Resolve path relative to baseDir if provided
+	path := reference
+	if baseDir != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	return filepath.Abs(path)
+}
+
+// includeDir returns the base location for the includes of an included file
+func includeDir(location string) string {
+	if isRemoteInclude(location) {
+		return location[:strings.LastIndex(location, "/")+1]
+	}
+	return filepath.Dir(location)
+}
+
+// IncludeCachePath returns the path of the cached copy of a remote include: an URL like
+// https://host/path/to/file.yaml is looked for at <cacheDir>/host/path/to/file.yaml
+func IncludeCachePath(cacheDir string, reference string) (string, error) {
+	if cacheDir == "" {
+		return "", fmt.Errorf("no cache directory for the remote include %s", reference)
+	}
+	u, err := url.Parse(reference)
+	if err != nil {
+		return "", fmt.Errorf("invalid remote include %s: %w", reference, err)
+	}
+	path := filepath.Join(cacheDir, u.Host, filepath.FromSlash(u.Path))
+	if !strings.HasPrefix(path, filepath.Clean(cacheDir)+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid remote include %s", reference)
+	}
+	return path, nil
+}
+
+// DefaultIncludeCacheDir returns the directory of the cached remote includes: the
+// YONTRACK_INCLUDE_CACHE environment variable or yontrack/includes in the user cache directory
+func DefaultIncludeCacheDir() string {
+	if dir := os.Getenv("YONTRACK_INCLUDE_CACHE"); dir != "" {
+		return dir
+	}
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "yontrack", "includes")
+	}
+	return ""
+}
+
 func RenderConfig(content string, vars map[string]string, envMap map[string]string) (string, error) {
 	// Map of functions
 	funcMap := sprig.TxtFuncMap()
@@ -125,6 +443,56 @@ func RenderConfig(content string, vars map[string]string, envMap map[string]stri
 		return defaultVal
 	}
 
+	// Git functions, reading the repository of the current directory
+	var repository *GitRepository
+	gitRepository := func() (*GitRepository, error) {
+		if repository == nil {
+			r, err := OpenGitRepository(".")
+			if err != nil {
+				return nil, err
+			}
+			repository = r
+		}
+		return repository, nil
+	}
+	funcMap["gitCommit"] = func() (string, error) {
+		r, err := gitRepository()
+		if err != nil {
+			return "", err
+		}
+		_, commit, err := r.Head()
+		return commit, err
+	}
+	funcMap["gitBranch"] = func() (string, error) {
+		r, err := gitRepository()
+		if err != nil {
+			return "", err
+		}
+		branch, _, err := r.Head()
+		return branch, err
+	}
+	funcMap["gitTag"] = func() (string, error) {
+		r, err := gitRepository()
+		if err != nil {
+			return "", err
+		}
+		return r.LatestTag()
+	}
+	funcMap["semverBump"] = SemverBump
+
+	// File functions, relative to the current directory
+	funcMap["readFile"] = func(path string) (string, error) {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(content), "\r\n"), nil
+	}
+	funcMap["fileExists"] = func(path string) bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}
+
 	// Creating the template
 	tmpl, err := template.New("yaml").Funcs(funcMap).Parse(content)
 	if err != nil {
@@ -338,3 +338,303 @@ func TestRenderConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderConfig_GitAndFileFunctions(t *testing.T) {
+	dir := createTestGitRepository(t)
+	writeFiles(t, dir, map[string]string{
+		"VERSION": "1.2.3\n",
+	})
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() {
+		_ = os.Chdir(cwd)
+	})
+
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "git commit",
+			content:  "commit: {{ gitCommit | trunc 7 }}",
+			expected: "commit: 4b825dc",
+		},
+		{
+			name:     "git branch",
+			content:  "branch: {{ gitBranch | replace \"/\" \"-\" }}",
+			expected: "branch: release-1.2",
+		},
+		{
+			name:     "git tag",
+			content:  "tag: {{ gitTag }}",
+			expected: "tag: v1.10.0",
+		},
+		{
+			name:     "semver bump of the latest tag",
+			content:  "next: {{ gitTag | semverBump \"minor\" }}",
+			expected: "next: v1.11.0",
+		},
+		{
+			name:     "read file",
+			content:  "version: {{ readFile \"VERSION\" }}",
+			expected: "version: 1.2.3",
+		},
+		{
+			name:     "file exists",
+			content:  "{{ if fileExists \"VERSION\" }}present{{ end }}{{ if not (fileExists \"missing\") }}, absent{{ end }}",
+			expected: "present, absent",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := RenderConfig(tt.content, nil, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestRenderConfig_ReadMissingFile(t *testing.T) {
+	_, err := RenderConfig("version: {{ readFile \"/nonexistent/VERSION\" }}", nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "error executing template")
+}
+
+func TestExpandConfig_Extends(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"base/ci.yaml": `
+version: v1
+configuration:
+  branch:
+    validations:
+      - build
+      - unit-tests
+    promotions:
+      - BRONZE
+    properties:
+      git: main
+      tags: [ci]
+`,
+	})
+
+	// Nested maps use @extends, an empty list extends nothing
+	input := `
+extends: ` + filepath.Join(tmpDir, "base", "ci.yaml") + `
+"@merge":
+  configuration: merge
+configuration:
+  branch:
+    "@extends": []
+    validations:
+      - deploy
+    properties:
+      tags: [override]
+`
+	result, err := ExpandConfig(input)
+	require.NoError(t, err)
+
+	expected := `configuration:
+  branch:
+    promotions:
+    - BRONZE
+    properties:
+      git: main
+      tags:
+      - override
+    validations:
+    - deploy
+version: v1
+`
+	assert.Equal(t, expected, result)
+}
+
+func TestExpandConfig_ExtendsWithAppend(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"base.yaml":   "validations: [build]\npromotions: {BRONZE: {}}\n",
+		"extra.yaml":  "validations: [lint]\n",
+		"branch.yaml": "extends: [base.yaml, extra.yaml]\n\"@merge\":\n  validations: append\n  promotions: replace\nvalidations: [deploy]\npromotions: {GOLD: {}}\n",
+	})
+
+	input := `
+branch: '@` + filepath.Join(tmpDir, "branch.yaml") + `'
+`
+	result, err := ExpandConfig(input)
+	require.NoError(t, err)
+
+	expected := `branch:
+  promotions:
+    GOLD: {}
+  validations:
+  - lint
+  - deploy
+`
+	assert.Equal(t, expected, result)
+}
+
+func TestExpandConfig_NestedMerge(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"base.yaml": "configuration:\n  defaults:\n    branch:\n      validations: [unit]\n      promotions: [BRONZE]\n",
+	})
+	base := filepath.Join(tmpDir, "base.yaml")
+	expected := `configuration:
+  defaults:
+    branch:
+      promotions:
+      - BRONZE
+      validations:
+      - unit
+      - extra
+`
+
+	// @merge in the nested map
+	result, err := ExpandConfig(`
+extends: ` + base + `
+configuration:
+  defaults:
+    branch:
+      "@merge":
+        validations: append
+      validations: [extra]
+`)
+	require.NoError(t, err)
+	assert.Equal(t, expected, result)
+
+	// @merge of an enclosing map
+	result, err = ExpandConfig(`
+extends: ` + base + `
+"@merge":
+  validations: append
+configuration:
+  defaults:
+    branch:
+      validations: [extra]
+`)
+	require.NoError(t, err)
+	assert.Equal(t, expected, result)
+
+	// A nested @merge takes priority
+	result, err = ExpandConfig(`
+extends: ` + base + `
+"@merge":
+  validations: append
+configuration:
+  defaults:
+    branch:
+      "@merge":
+        validations: replace
+      validations: [extra]
+`)
+	require.NoError(t, err)
+	assert.Contains(t, result, "validations:\n      - extra\n")
+	assert.NotContains(t, result, "- unit")
+}
+
+func TestExpandConfig_ExtendsOnlyAtRoot(t *testing.T) {
+	// extends is a regular key outside of the root of a document
+	result, err := ExpandConfig(`
+project:
+  properties:
+    extends: x
+`)
+	require.NoError(t, err)
+	assert.Equal(t, "project:\n  properties:\n    extends: x\n", result)
+}
+
+func TestExpandConfig_MergeWithoutEffect(t *testing.T) {
+	_, err := ExpandConfig(`
+project:
+  properties:
+    "@merge":
+      validations: append
+    validations: [extra]
+`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "@merge at project.properties has no effect")
+
+	// The overridden map does not exist in the extended file
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"base.yaml": "version: v1\n",
+	})
+	_, err = ExpandConfig("extends: " + filepath.Join(tmpDir, "base.yaml") + "\nbranch:\n  \"@merge\":\n    validations: append\n  validations: [extra]\n")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "@merge at branch has no effect")
+}
+
+func TestExpandConfig_InvalidMergeStrategy(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"base.yaml": "validations: [build]\n",
+	})
+
+	_, err := ExpandConfig("extends: " + filepath.Join(tmpDir, "base.yaml") + "\n\"@merge\":\n  validations: prepend\n")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid @merge strategy prepend")
+
+	_, err = ExpandConfig("extends: " + filepath.Join(tmpDir, "base.yaml") + "\n\"@merge\":\n  validations: append\nvalidations: {}\n")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot append validations")
+}
+
+func TestExpandConfig_IncludeCycle(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"a.yaml": "extends: b.yaml\nname: a\n",
+		"b.yaml": "child: '@c.yaml'\n",
+		"c.yaml": "extends: a.yaml\n",
+	})
+
+	_, err := ExpandConfig("config: '@" + filepath.Join(tmpDir, "a.yaml") + "'")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "include cycle detected")
+	assert.Contains(t, err.Error(), filepath.Join(tmpDir, "a.yaml")+" -> "+filepath.Join(tmpDir, "b.yaml")+" -> "+filepath.Join(tmpDir, "c.yaml")+" -> "+filepath.Join(tmpDir, "a.yaml"))
+}
+
+func TestExpandConfig_SelfIncludeCycle(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "ci.yaml")
+	content := "extends: ci.yaml\n"
+	writeFiles(t, tmpDir, map[string]string{"ci.yaml": content})
+
+	_, err := ExpandConfigWithOptions("extends: "+path+"\n", ExpandOptions{Path: path})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "include cycle detected")
+}
+
+func TestExpandConfig_RemoteIncludeFromCache(t *testing.T) {
+	cacheDir := t.TempDir()
+	writeFiles(t, cacheDir, map[string]string{
+		"example.com/templates/base.yaml":            "extends: common/defaults.yaml\nbranch: main\n",
+		"example.com/templates/common/defaults.yaml": "timeout: 10\n",
+	})
+
+	result, err := ExpandConfigWithOptions(`
+config:
+  "@extends": https://example.com/templates/base.yaml
+  branch: release
+`, ExpandOptions{CacheDir: cacheDir})
+	require.NoError(t, err)
+	assert.Equal(t, "config:\n  branch: release\n  timeout: 10\n", result)
+
+	_, err = ExpandConfigWithOptions("config: '@https://example.com/missing.yaml'", ExpandOptions{CacheDir: cacheDir})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "remote include https://example.com/missing.yaml not found in the cache")
+}
+
+func TestIncludeCachePath(t *testing.T) {
+	path, err := IncludeCachePath("/cache", "https://example.com/org/ci/base.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("/cache", "example.com", "org", "ci", "base.yaml"), path)
+
+	_, err = IncludeCachePath("", "https://example.com/base.yaml")
+	assert.Error(t, err)
+
+	_, err = IncludeCachePath("/cache", "https://example.com/../../etc/passwd")
+	assert.Error(t, err)
+}
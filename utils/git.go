@@ -0,0 +1,235 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// GitRepository gives access to a local Git repository by reading its .git directory,
+// without needing the git executable
+type GitRepository struct {
+	// Directory containing HEAD
+	gitDir string
+	// Directory containing the refs (different from gitDir for worktrees)
+	commonDir string
+}
+
+// OpenGitRepository looks for the Git repository containing the given directory
+func OpenGitRepository(dir string) (*GitRepository, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		gitDir, err := resolveGitDir(filepath.Join(dir, ".git"))
+		if err != nil {
+			return nil, err
+		}
+		if gitDir != "" {
+			repository := &GitRepository{gitDir: gitDir, commonDir: gitDir}
+			if content, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+				commonDir := strings.TrimSpace(string(content))
+				if !filepath.IsAbs(commonDir) {
+					commonDir = filepath.Join(gitDir, commonDir)
+				}
+				repository.commonDir = filepath.Clean(commonDir)
+			}
+			return repository, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, fmt.Errorf("no Git repository found")
+		}
+		dir = parent
+	}
+}
+
+// resolveGitDir returns the Git directory for a .git entry, which is either a directory
+// or, for worktrees and submodules, a file pointing to the actual directory.
+// An empty string is returned if the entry does not exist.
+func resolveGitDir(path string) (string, error) {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		return path, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	line := strings.TrimSpace(string(content))
+	if !strings.HasPrefix(line, "gitdir:") {
+		return "", fmt.Errorf("invalid .git file at %s", path)
+	}
+	gitDir := strings.TrimSpace(strings.TrimPrefix(line, "gitdir:"))
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(filepath.Dir(path), gitDir)
+	}
+	return filepath.Clean(gitDir), nil
+}
+
+// Head returns the current branch (empty when the HEAD is detached) and the current commit
+func (r *GitRepository) Head() (string, string, error) {
+	content, err := os.ReadFile(filepath.Join(r.gitDir, "HEAD"))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read Git HEAD: %w", err)
+	}
+	head := strings.TrimSpace(string(content))
+	if !strings.HasPrefix(head, "ref:") {
+		return "", head, nil
+	}
+	ref := strings.TrimSpace(strings.TrimPrefix(head, "ref:"))
+	commit, err := r.ResolveRef(ref)
+	if err != nil {
+		return "", "", err
+	}
+	return strings.TrimPrefix(ref, "refs/heads/"), commit, nil
+}
+
+// ResolveRef returns the commit a reference like refs/heads/main points to, or an empty
+// string for a branch without any commit yet
+func (r *GitRepository) ResolveRef(ref string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(r.commonDir, filepath.FromSlash(ref)))
+	if err == nil {
+		return strings.TrimSpace(string(content)), nil
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+	packed, err := r.packedRefs()
+	if err != nil {
+		return "", err
+	}
+	return packed[ref].target, nil
+}
+
+type packedRef struct {
+	target string
+	// Commit an annotated tag points to
+	peeled string
+}
+
+func (r *GitRepository) packedRefs() (map[string]packedRef, error) {
+	refs := make(map[string]packedRef)
+	file, err := os.Open(filepath.Join(r.commonDir, "packed-refs"))
+	if os.IsNotExist(err) {
+		return refs, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	last := ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(line, "^"):
+			if ref, ok := refs[last]; ok {
+				ref.peeled = strings.TrimPrefix(line, "^")
+				refs[last] = ref
+			}
+		default:
+			parts := strings.Fields(line)
+			if len(parts) == 2 {
+				refs[parts[1]] = packedRef{target: parts[0]}
+				last = parts[1]
+			}
+		}
+	}
+	return refs, scanner.Err()
+}
+
+// Tags returns the names of all the tags of the repository
+func (r *GitRepository) Tags() ([]string, error) {
+	names := make(map[string]bool)
+	packed, err := r.packedRefs()
+	if err != nil {
+		return nil, err
+	}
+	for ref := range packed {
+		if strings.HasPrefix(ref, "refs/tags/") {
+			names[strings.TrimPrefix(ref, "refs/tags/")] = true
+		}
+	}
+	tagsDir := filepath.Join(r.commonDir, "refs", "tags")
+	err = filepath.Walk(tagsDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !info.IsDir() {
+			name, err := filepath.Rel(tagsDir, path)
+			if err != nil {
+				return err
+			}
+			names[filepath.ToSlash(name)] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	tags := make([]string, 0, len(names))
+	for name := range names {
+		tags = append(tags, name)
+	}
+	sort.Strings(tags)
+	return tags, nil
+}
+
+// LatestTag returns the tag with the highest semantic version, ignoring the tags which are
+// not versions. An empty string is returned if there is no such tag.
+func (r *GitRepository) LatestTag() (string, error) {
+	tags, err := r.Tags()
+	if err != nil {
+		return "", err
+	}
+	latest := ""
+	var latestVersion *semver.Version
+	for _, tag := range tags {
+		version, err := semver.NewVersion(tag)
+		if err != nil {
+			continue
+		}
+		if latestVersion == nil || version.GreaterThan(latestVersion) {
+			latest = tag
+			latestVersion = version
+		}
+	}
+	return latest, nil
+}
+
+// SemverBump increments the major, minor or patch part of a version, keeping its v prefix if any
+func SemverBump(part string, version string) (string, error) {
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return "", fmt.Errorf("invalid version %q: %w", version, err)
+	}
+	var bumped semver.Version
+	switch strings.ToLower(part) {
+	case "major":
+		bumped = v.IncMajor()
+	case "minor":
+		bumped = v.IncMinor()
+	case "patch":
+		bumped = v.IncPatch()
+	default:
+		return "", fmt.Errorf("invalid version part %q (expected major, minor or patch)", part)
+	}
+	if strings.HasPrefix(version, "v") {
+		return "v" + bumped.String(), nil
+	}
+	return bumped.String(), nil
+}
@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testCommit = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+	testTagged = "9fceb02d0ae598e95dc970b74767f19372d61af8"
+)
+
+// writeFiles creates the given files, relative to dir
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+// createTestGitRepository creates a minimal .git directory, with a main branch, loose and packed tags
+func createTestGitRepository(t *testing.T) string {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		".git/HEAD":                   "ref: refs/heads/release/1.2\n",
+		".git/refs/heads/release/1.2": testCommit + "\n",
+		".git/refs/tags/v1.2.0":       testTagged + "\n",
+		".git/refs/tags/nightly":      testCommit + "\n",
+		".git/packed-refs": "# pack-refs with: peeled fully-peeled sorted\n" +
+			testTagged + " refs/heads/main\n" +
+			testTagged + " refs/tags/v1.10.0\n" +
+			"^" + testCommit + "\n" +
+			testTagged + " refs/tags/v1.9.3\n",
+	})
+	return dir
+}
+
+func TestGitRepository_Head(t *testing.T) {
+	dir := createTestGitRepository(t)
+
+	repository, err := OpenGitRepository(dir)
+	require.NoError(t, err)
+	branch, commit, err := repository.Head()
+	require.NoError(t, err)
+	assert.Equal(t, "release/1.2", branch)
+	assert.Equal(t, testCommit, commit)
+
+	packed, err := repository.ResolveRef("refs/heads/main")
+	require.NoError(t, err)
+	assert.Equal(t, testTagged, packed)
+}
+
+func TestGitRepository_DetachedHeadFromSubdirectory(t *testing.T) {
+	dir := createTestGitRepository(t)
+	writeFiles(t, dir, map[string]string{
+		".git/HEAD":   testTagged + "\n",
+		"src/main.go": "package main\n",
+	})
+
+	repository, err := OpenGitRepository(filepath.Join(dir, "src"))
+	require.NoError(t, err)
+	branch, commit, err := repository.Head()
+	require.NoError(t, err)
+	assert.Equal(t, "", branch)
+	assert.Equal(t, testTagged, commit)
+}
+
+func TestGitRepository_Worktree(t *testing.T) {
+	dir := createTestGitRepository(t)
+	worktree := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		".git/worktrees/feature/HEAD":      "ref: refs/heads/main\n",
+		".git/worktrees/feature/commondir": "../..\n",
+	})
+	writeFiles(t, worktree, map[string]string{
+		".git": "gitdir: " + filepath.Join(dir, ".git", "worktrees", "feature") + "\n",
+	})
+
+	repository, err := OpenGitRepository(worktree)
+	require.NoError(t, err)
+	branch, commit, err := repository.Head()
+	require.NoError(t, err)
+	assert.Equal(t, "main", branch)
+	assert.Equal(t, testTagged, commit)
+}
+
+func TestGitRepository_Tags(t *testing.T) {
+	repository, err := OpenGitRepository(createTestGitRepository(t))
+	require.NoError(t, err)
+
+	tags, err := repository.Tags()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"nightly", "v1.10.0", "v1.2.0", "v1.9.3"}, tags)
+
+	latest, err := repository.LatestTag()
+	require.NoError(t, err)
+	assert.Equal(t, "v1.10.0", latest)
+}
+
+func TestOpenGitRepository_NotFound(t *testing.T) {
+	_, err := OpenGitRepository(t.TempDir())
+	assert.Error(t, err)
+}
+
+func TestSemverBump(t *testing.T) {
+	tests := []struct {
+		part     string
+		version  string
+		expected string
+	}{
+		{"major", "1.2.3", "2.0.0"},
+		{"minor", "1.2.3", "1.3.0"},
+		{"patch", "1.2.3", "1.2.4"},
+		{"patch", "v1.2.3", "v1.2.4"},
+		{"minor", "1.2.3-rc.1", "1.3.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.part+" "+tt.version, func(t *testing.T) {
+			actual, err := SemverBump(tt.part, tt.version)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+
+	_, err := SemverBump("minor", "not-a-version")
+	assert.Error(t, err)
+	_, err = SemverBump("build", "1.2.3")
+	assert.Error(t, err)
+}
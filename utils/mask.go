@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MaskedValue replaces the secret values in the diagnostics and logs
+const MaskedValue = "****"
+
+// DefaultSecretPatterns are the patterns identifying the keys whose value is a secret
+var DefaultSecretPatterns = []string{"TOKEN", "PASSWORD", "SECRET", "KEY"}
+
+// Masker hides secret values in the texts displayed by the CLI.
+//
+// A value is considered secret when it has been registered explicitly (like the
+// Ontrack token) or when its key contains one of the patterns, ignoring case.
+type Masker struct {
+	lock     sync.RWMutex
+	patterns []string
+	values   []string
+}
+
+// NewMasker creates a masker using the given key patterns
+func NewMasker(patterns []string) *Masker {
+	m := &Masker{}
+	m.SetPatterns(patterns)
+	return m
+}
+
+// Secrets is the masker shared by the whole CLI
+var Secrets = NewMasker(DefaultSecretPatterns)
+
+// SetPatterns replaces the patterns identifying the secret keys
+func (m *Masker) SetPatterns(patterns []string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.patterns = make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			m.patterns = append(m.patterns, strings.ToUpper(pattern))
+		}
+	}
+}
+
+// IsSecretKey checks if the value of a key must be masked
+func (m *Masker) IsSecretKey(key string) bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	key = strings.ToUpper(key)
+	for _, pattern := range m.patterns {
+		if strings.Contains(key, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddValue registers a secret value, which is then masked wherever it appears
+func (m *Masker) AddValue(value string) {
+	if value == "" {
+		return
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, existing := range m.values {
+		if existing == value {
+			return
+		}
+	}
+	m.values = append(m.values, value)
+	// Longest values first, so that a secret containing another one is fully masked
+	sort.SliceStable(m.values, func(i, j int) bool {
+		return len(m.values[i]) > len(m.values[j])
+	})
+}
+
+// AddEnv registers the values of the variables whose key is secret
+func (m *Masker) AddEnv(env map[string]string) {
+	for key, value := range env {
+		if m.IsSecretKey(key) {
+			m.AddValue(value)
+		}
+	}
+}
+
+// MaskValue returns the value to display for a key
+func (m *Masker) MaskValue(key string, value string) string {
+	if value != "" && m.IsSecretKey(key) {
+		return MaskedValue
+	}
+	return m.Mask(value)
+}
+
+// Mask replaces all the registered secret values in a text
+func (m *Masker) Mask(text string) string {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	for _, value := range m.values {
+		text = strings.ReplaceAll(text, value, MaskedValue)
+	}
+	return text
+}
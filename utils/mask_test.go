@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMasker_IsSecretKey(t *testing.T) {
+	m := NewMasker(DefaultSecretPatterns)
+	assert.True(t, m.IsSecretKey("GITHUB_TOKEN"))
+	assert.True(t, m.IsSecretKey("db_password"))
+	assert.True(t, m.IsSecretKey("AWS_SECRET_ACCESS_KEY"))
+	assert.True(t, m.IsSecretKey("X-Ontrack-Token"))
+	assert.False(t, m.IsSecretKey("GIT_BRANCH"))
+
+	m.SetPatterns([]string{"credential", " "})
+	assert.True(t, m.IsSecretKey("NPM_CREDENTIALS"))
+	assert.False(t, m.IsSecretKey("GITHUB_TOKEN"))
+}
+
+func TestMasker_Mask(t *testing.T) {
+	m := NewMasker(DefaultSecretPatterns)
+	m.AddEnv(map[string]string{
+		"GITHUB_TOKEN": "ghp_123",
+		"API_KEY":      "ghp_123456",
+		"GIT_BRANCH":   "main",
+		"EMPTY_SECRET": "",
+	})
+	m.AddValue("s3cr3t")
+
+	assert.Equal(t, "token: ****, key: ****, branch: main, other: ****",
+		m.Mask("token: ghp_123, key: ghp_123456, branch: main, other: s3cr3t"))
+	assert.Equal(t, "****", m.MaskValue("NEW_TOKEN", "not registered"))
+	assert.Equal(t, "", m.MaskValue("EMPTY_SECRET", ""))
+	assert.Equal(t, "main", m.MaskValue("GIT_BRANCH", "main"))
+}
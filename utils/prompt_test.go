@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		answer   string
+		expected bool
+	}{
+		{"y\n", true},
+		{"YES\n", true},
+		{" yes ", true},
+		{"n\n", false},
+		{"\n", false},
+		{"", false},
+		{"maybe\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.answer, func(t *testing.T) {
+			var out bytes.Buffer
+			ok, err := Confirm(strings.NewReader(tt.answer), &out, "Delete?")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, ok)
+			assert.Equal(t, "Delete? [y/N] ", out.String())
+		})
+	}
+}
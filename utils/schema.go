@@ -0,0 +1,339 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// SchemaError is an error found when validating a YAML document against a JSON schema
+type SchemaError struct {
+	Line   int
+	Column int
+	// Path of the element in error, like configuration.branch.validations
+	Path    string
+	Message string
+}
+
+func (e SchemaError) String() string {
+	if e.Path == "" {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+	}
+	return fmt.Sprintf("line %d: %s: %s", e.Line, e.Path, e.Message)
+}
+
+// SchemaErrors is the list of errors returned when a YAML document does not match its schema
+type SchemaErrors []SchemaError
+
+func (e SchemaErrors) Error() string {
+	var b strings.Builder
+	for _, item := range e {
+		b.WriteString(item.String())
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// ValidateYAML validates a YAML document against a JSON schema, returning the list
+// of errors with their position in the document.
+//
+// Only a subset of the JSON schema specification is supported: type, enum, const,
+// properties, required, additionalProperties, patternProperties, items, minItems,
+// maxItems, pattern, anyOf, oneOf, allOf and local $ref. Other keywords are ignored.
+func ValidateYAML(content string, schema []byte) (SchemaErrors, error) {
+	var root map[string]interface{}
+	if err := json.Unmarshal(schema, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON schema: %w", err)
+	}
+
+	var document yamlv3.Node
+	if err := yamlv3.Unmarshal([]byte(content), &document); err != nil {
+		return SchemaErrors{yamlSyntaxError(err)}, nil
+	}
+	node := &document
+	if node.Kind == yamlv3.DocumentNode {
+		if len(node.Content) == 0 {
+			return SchemaErrors{{Line: 1, Message: "empty document"}}, nil
+		}
+		node = node.Content[0]
+	}
+
+	v := &schemaValidator{root: root}
+	v.validate(node, root, "")
+	sort.SliceStable(v.errors, func(i, j int) bool {
+		return v.errors[i].Line < v.errors[j].Line
+	})
+	return v.errors, nil
+}
+
+var yamlLineRegex = regexp.MustCompile(`line (\d+): `)
+
+func yamlSyntaxError(err error) SchemaError {
+	message := strings.TrimPrefix(err.Error(), "yaml: ")
+	line := 0
+	if match := yamlLineRegex.FindStringSubmatchIndex(message); match != nil {
+		line, _ = strconv.Atoi(message[match[2]:match[3]])
+		message = message[:match[0]] + message[match[1]:]
+	}
+	return SchemaError{Line: line, Message: message}
+}
+
+type schemaValidator struct {
+	root   map[string]interface{}
+	errors SchemaErrors
+}
+
+func (v *schemaValidator) fail(node *yamlv3.Node, path string, format string, args ...interface{}) {
+	v.errors = append(v.errors, SchemaError{
+		Line:    node.Line,
+		Column:  node.Column,
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// matches checks if a node is valid against a schema, without recording any error
+func (v *schemaValidator) matches(node *yamlv3.Node, schema map[string]interface{}) bool {
+	probe := &schemaValidator{root: v.root}
+	probe.validate(node, schema, "")
+	return len(probe.errors) == 0
+}
+
+func (v *schemaValidator) resolve(schema map[string]interface{}) map[string]interface{} {
+	for i := 0; i < 32; i++ {
+		ref, ok := schema["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#") {
+			return schema
+		}
+		var target interface{} = v.root
+		for _, part := range strings.Split(strings.TrimPrefix(ref, "#"), "/") {
+			if part == "" {
+				continue
+			}
+			object, ok := target.(map[string]interface{})
+			if !ok {
+				return map[string]interface{}{}
+			}
+			target = object[strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")]
+		}
+		resolved, ok := target.(map[string]interface{})
+		if !ok {
+			return map[string]interface{}{}
+		}
+		schema = resolved
+	}
+	return schema
+}
+
+func (v *schemaValidator) validate(node *yamlv3.Node, schema map[string]interface{}, path string) {
+	schema = v.resolve(schema)
+	if node.Kind == yamlv3.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+
+	if types := schemaTypes(schema["type"]); len(types) > 0 && !nodeHasType(node, types) {
+		v.fail(node, path, "expected %s but got %s", strings.Join(types, " or "), nodeType(node))
+		return
+	}
+	if values, ok := schema["enum"].([]interface{}); ok && !nodeInValues(node, values) {
+		v.fail(node, path, "value %q is not one of %s", node.Value, formatValues(values))
+	}
+	if value, ok := schema["const"]; ok && !nodeInValues(node, []interface{}{value}) {
+		v.fail(node, path, "value %q must be %s", node.Value, formatValues([]interface{}{value}))
+	}
+	if pattern, ok := schema["pattern"].(string); ok && node.Kind == yamlv3.ScalarNode {
+		if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(node.Value) {
+			v.fail(node, path, "value %q does not match %s", node.Value, pattern)
+		}
+	}
+
+	if all, ok := schema["allOf"].([]interface{}); ok {
+		for _, item := range all {
+			if sub, ok := item.(map[string]interface{}); ok {
+				v.validate(node, sub, path)
+			}
+		}
+	}
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		if count := v.countMatches(node, anyOf); count == 0 {
+			v.fail(node, path, "does not match any of the allowed schemas")
+		}
+	}
+	if oneOf, ok := schema["oneOf"].([]interface{}); ok {
+		if count := v.countMatches(node, oneOf); count != 1 {
+			v.fail(node, path, "must match exactly one of the allowed schemas (matches %d)", count)
+		}
+	}
+
+	switch node.Kind {
+	case yamlv3.MappingNode:
+		v.validateObject(node, schema, path)
+	case yamlv3.SequenceNode:
+		v.validateArray(node, schema, path)
+	}
+}
+
+func (v *schemaValidator) countMatches(node *yamlv3.Node, schemas []interface{}) int {
+	count := 0
+	for _, item := range schemas {
+		if sub, ok := item.(map[string]interface{}); ok && v.matches(node, sub) {
+			count++
+		}
+	}
+	return count
+}
+
+func (v *schemaValidator) validateObject(node *yamlv3.Node, schema map[string]interface{}, path string) {
+	properties, _ := schema["properties"].(map[string]interface{})
+	patternProperties, _ := schema["patternProperties"].(map[string]interface{})
+	present := make(map[string]bool)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		present[key.Value] = true
+		childPath := joinSchemaPath(path, key.Value)
+		matched := false
+		if sub, ok := properties[key.Value].(map[string]interface{}); ok {
+			v.validate(value, sub, childPath)
+			matched = true
+		}
+		for pattern, item := range patternProperties {
+			if re, err := regexp.Compile(pattern); err == nil && re.MatchString(key.Value) {
+				if sub, ok := item.(map[string]interface{}); ok {
+					v.validate(value, sub, childPath)
+				}
+				matched = true
+			}
+		}
+		if matched {
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				v.fail(key, path, "unknown key %s", key.Value)
+			}
+		case map[string]interface{}:
+			v.validate(value, additional, childPath)
+		}
+	}
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, item := range required {
+			if name, ok := item.(string); ok && !present[name] {
+				v.fail(node, path, "missing required key %s", name)
+			}
+		}
+	}
+}
+
+func (v *schemaValidator) validateArray(node *yamlv3.Node, schema map[string]interface{}, path string) {
+	if min, ok := schema["minItems"].(float64); ok && float64(len(node.Content)) < min {
+		v.fail(node, path, "expected at least %d item(s)", int(min))
+	}
+	if max, ok := schema["maxItems"].(float64); ok && float64(len(node.Content)) > max {
+		v.fail(node, path, "expected at most %d item(s)", int(max))
+	}
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		for index, item := range node.Content {
+			v.validate(item, items, fmt.Sprintf("%s[%d]", path, index))
+		}
+	}
+}
+
+func joinSchemaPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func schemaTypes(value interface{}) []string {
+	switch t := value.(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		var types []string
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+// nodeType returns the JSON type of a YAML node
+func nodeType(node *yamlv3.Node) string {
+	switch node.Kind {
+	case yamlv3.MappingNode:
+		return "object"
+	case yamlv3.SequenceNode:
+		return "array"
+	}
+	switch node.ShortTag() {
+	case "!!null":
+		return "null"
+	case "!!bool":
+		return "boolean"
+	case "!!int":
+		return "integer"
+	case "!!float":
+		return "number"
+	}
+	return "string"
+}
+
+func nodeHasType(node *yamlv3.Node, types []string) bool {
+	actual := nodeType(node)
+	for _, expected := range types {
+		if expected == actual {
+			return true
+		}
+		if expected == "number" && actual == "integer" {
+			return true
+		}
+		if expected == "integer" && actual == "number" {
+			if f, err := strconv.ParseFloat(node.Value, 64); err == nil && f == math.Trunc(f) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func nodeInValues(node *yamlv3.Node, values []interface{}) bool {
+	if node.Kind != yamlv3.ScalarNode {
+		return false
+	}
+	for _, value := range values {
+		switch expected := value.(type) {
+		case string:
+			if nodeType(node) == "string" && node.Value == expected {
+				return true
+			}
+		case nil:
+			if nodeType(node) == "null" {
+				return true
+			}
+		default:
+			if node.Value == fmt.Sprint(expected) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func formatValues(values []interface{}) string {
+	items := make([]string, 0, len(values))
+	for _, value := range values {
+		items = append(items, fmt.Sprintf("%v", value))
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSchema = `{
+	"type": "object",
+	"required": ["version"],
+	"additionalProperties": false,
+	"properties": {
+		"version": {"type": "string", "enum": ["v1"]},
+		"configuration": {"$ref": "#/definitions/configuration"}
+	},
+	"definitions": {
+		"configuration": {
+			"type": "object",
+			"additionalProperties": false,
+			"properties": {
+				"branch": {
+					"type": "object",
+					"properties": {
+						"validations": {"type": "array", "items": {"type": "string"}}
+					}
+				},
+				"count": {"type": "integer"}
+			}
+		}
+	}
+}`
+
+func TestValidateYAML(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []string
+	}{
+		{
+			name: "valid",
+			content: `version: v1
+configuration:
+  branch:
+    validations:
+      - build
+  count: 3
+`,
+		},
+		{
+			name: "unknown key",
+			content: `version: v1
+configuration:
+  brnach:
+    validations: []
+`,
+			expected: []string{"line 3: configuration: unknown key brnach"},
+		},
+		{
+			name:     "missing required key",
+			content:  "configuration: {}\n",
+			expected: []string{"line 1: missing required key version"},
+		},
+		{
+			name:     "enum",
+			content:  "version: v2\n",
+			expected: []string{`line 1: version: value "v2" is not one of [v1]`},
+		},
+		{
+			name: "type mismatch",
+			content: `version: v1
+configuration:
+  count: many
+  branch:
+    validations: build
+`,
+			expected: []string{
+				"line 3: configuration.count: expected integer but got string",
+				"line 5: configuration.branch.validations: expected array but got string",
+			},
+		},
+		{
+			name: "array items",
+			content: `version: v1
+configuration:
+  branch:
+    validations:
+      - build
+      - name: test
+`,
+			expected: []string{"line 6: configuration.branch.validations[1]: expected string but got object"},
+		},
+		{
+			name:     "syntax error",
+			content:  "version: v1\nconfiguration:\n  branch: [\n",
+			expected: []string{"line 3: did not find expected node content"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors, err := ValidateYAML(tt.content, []byte(testSchema))
+			require.NoError(t, err)
+			var actual []string
+			for _, item := range errors {
+				actual = append(actual, item.String())
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestValidateYAML_InvalidSchema(t *testing.T) {
+	_, err := ValidateYAML("version: v1", []byte("{"))
+	assert.Error(t, err)
+}